@@ -0,0 +1,120 @@
+package clapper
+
+import (
+	"sort"
+	"strings"
+)
+
+// positional value of the command-line arguments
+type positionalValue struct {
+	value string
+
+	// index of the value in the command-line arguments
+	index int
+
+	// if the value is after the `--` marker (it is stored in `CommandParsed.Passthrough` if it is not bound)
+	afterEnd bool
+}
+
+// SetNamedArgs sets if the arguments of the command can be passed by name like flags: `--<arg>=<value>`
+// or `--<arg> <value>` (a variadic argument can be repeated). A flag with the same name takes precedence.
+// The named arguments are not matched with the positional values.
+func (commandConfig *CommandConfig) SetNamedArgs(named bool) *CommandConfig {
+	commandConfig.NamedArgs = named
+	return commandConfig
+}
+
+// SetNonGreedy sets if a variadic argument is non-greedy. A greedy variadic argument (default) takes all values
+// except the values needed by the required arguments after it, a non-greedy variadic argument takes
+// the values left by the optional arguments after it. For example, with `[a...] [b]` arguments and `x y` values,
+// the greedy `a` argument takes both values and the non-greedy `a` argument takes `x` value only.
+func (a *ArgCommand) SetNonGreedy(nonGreedy bool) *ArgCommand {
+	a.NonGreedy = nonGreedy
+	return a
+}
+
+// return the named argument of a flag value (`--<arg>`), nil if the arguments are not named or there is no argument
+func (commandConfig *CommandConfig) namedArg(value string) *ArgCommand {
+	if !commandConfig.NamedArgs || isShortFlag(value) || !strings.HasPrefix(value, "--") {
+		return nil
+	}
+	return commandConfig.Args[strings.TrimPrefix(value, "--")]
+}
+
+// validate a value of the argument and store it (a value of a variadic argument is appended)
+func (a *ArgCommand) bind(store *CommandParsed, value string) error {
+	if !a.Validate(value) {
+		return ErrorUnsupportedValue{a.Name, value}
+	}
+
+	if arg, exist := store.Args[a.Name]; exist && a.IsVariadic {
+		arg.Value += "," + value
+	} else {
+		store.Args[a.Name] = a.Store(value)
+	}
+	return nil
+}
+
+// bind the positional values to the arguments which are not passed by name
+//
+// The number of the values of each argument is between the minimum (1 for a required argument, 0 for an optional one)
+// and the maximum (1, unlimited for a variadic argument). The required arguments get their minimum first
+// (from the left if there are not enough values), then the optional and greedy variadic arguments take
+// the remaining values from the left and the non-greedy variadic arguments take the rest.
+// The values which are not bound are stored in `CommandParsed.Passthrough` if they are after `--`.
+func (commandConfig *CommandConfig) matchArgs(store *CommandParsed, positionals []positionalValue, values []string) error {
+	args := make([]*ArgCommand, 0, len(commandConfig.ArgNames))
+	for _, name := range commandConfig.ArgNames {
+		if _, ok := store.Args[name]; !ok {
+			args = append(args, commandConfig.Args[name])
+		}
+	}
+
+	// number of the values of each argument
+	counts := make([]int, len(args))
+	remaining := len(positionals)
+	for i, arg := range args {
+		if arg.Required && remaining > 0 {
+			counts[i] = 1
+			remaining--
+		}
+	}
+	for _, nonGreedy := range []bool{false, true} {
+		for i, arg := range args {
+			switch {
+			case remaining == 0 || (arg.IsVariadic && arg.NonGreedy) != nonGreedy:
+			case arg.IsVariadic:
+				counts[i] += remaining
+				remaining = 0
+			case counts[i] == 0:
+				counts[i] = 1
+				remaining--
+			}
+		}
+	}
+
+	// bind the values
+	next := 0
+	for i, arg := range args {
+		for ; counts[i] > 0; counts[i]-- {
+			positional := positionals[next]
+			if err := arg.bind(store, positional.value); err != nil {
+				return err
+			}
+			store.addArgOccurrence(arg.Name, positional.value, positional.index, values)
+			next++
+		}
+	}
+	for _, positional := range positionals[next:] {
+		if positional.afterEnd {
+			store.Passthrough = append(store.Passthrough, positional.value)
+		}
+	}
+
+	// occurrences of the arguments in the order of the command-line arguments
+	sort.SliceStable(store.Occurrences, func(i, j int) bool {
+		return store.Occurrences[i].Index < store.Occurrences[j].Index
+	})
+
+	return nil
+}
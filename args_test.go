@@ -0,0 +1,140 @@
+package clapper_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/msaf1980/clapper"
+	"github.com/msaf1980/clapper/clappertest"
+)
+
+// test matching of the positional values with the arguments
+func TestMatchArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		register    func(commandConfig *clapper.CommandConfig)
+		args        []string
+		argVals     map[string]string
+		passthrough []string
+	}{
+		{
+			name: "variadic before required",
+			register: func(commandConfig *clapper.CommandConfig) {
+				commandConfig.AddArg("src...", "")
+				arg, _ := commandConfig.AddArg("dest", "")
+				arg.SetRequired(true)
+			},
+			args:    []string{"a", "-v", "b", "c"},
+			argVals: map[string]string{"src": "a,b", "dest": "c"},
+		},
+		{
+			name: "optional before required",
+			register: func(commandConfig *clapper.CommandConfig) {
+				commandConfig.AddArg("mode", "644")
+				arg, _ := commandConfig.AddArg("file", "")
+				arg.SetRequired(true)
+			},
+			args:    []string{"x"},
+			argVals: map[string]string{"mode": "644", "file": "x"},
+		},
+		{
+			name: "optional and required",
+			register: func(commandConfig *clapper.CommandConfig) {
+				commandConfig.AddArg("mode", "644")
+				arg, _ := commandConfig.AddArg("file", "")
+				arg.SetRequired(true)
+			},
+			args:    []string{"755", "x"},
+			argVals: map[string]string{"mode": "755", "file": "x"},
+		},
+		{
+			name: "greedy",
+			register: func(commandConfig *clapper.CommandConfig) {
+				commandConfig.AddArg("files...", "")
+				commandConfig.AddArg("target", "default")
+			},
+			args:    []string{"x", "y"},
+			argVals: map[string]string{"files": "x,y", "target": "default"},
+		},
+		{
+			name: "non-greedy",
+			register: func(commandConfig *clapper.CommandConfig) {
+				arg, _ := commandConfig.AddArg("files...", "")
+				arg.SetNonGreedy(true)
+				commandConfig.AddArg("target", "default")
+			},
+			args:    []string{"x", "y", "z"},
+			argVals: map[string]string{"files": "x,y", "target": "z"},
+		},
+		{
+			name: "passthrough",
+			register: func(commandConfig *clapper.CommandConfig) {
+				commandConfig.AddArg("first", "")
+				commandConfig.AddArg("second", "")
+			},
+			args:        []string{"x", "--", "-y", "z", "w"},
+			argVals:     map[string]string{"first": "x", "second": "-y"},
+			passthrough: []string{"z", "w"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := clapper.NewRegistry()
+			rootCommand, _ := registry.Register("")
+			rootCommand.AddFlag("verbose", "v", true, "")
+			tt.register(rootCommand)
+
+			command := clappertest.Parse(t, registry, tt.args...)
+			clappertest.AssertArgs(t, command, tt.argVals)
+			if tt.passthrough == nil {
+				tt.passthrough = []string{}
+			}
+			if !reflect.DeepEqual(command.Passthrough, tt.passthrough) {
+				t.Errorf("got passthrough %q, want %q", command.Passthrough, tt.passthrough)
+			}
+		})
+	}
+
+	// the missing required argument
+	registry := clapper.NewRegistry()
+	rootCommand, _ := registry.Register("")
+	arg, _ := rootCommand.AddArg("src...", "")
+	arg.SetRequired(true)
+	arg, _ = rootCommand.AddArg("dest", "")
+	arg.SetRequired(true)
+	result := clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"a"}})
+	clappertest.AssertError(t, result.Err, clapper.ErrorMissingArg{Name: "dest"})
+}
+
+// test the arguments passed by name
+func TestNamedArgs(t *testing.T) {
+	registry := newDemoRegistry(false)
+	registry.Commands["info"].SetNamedArgs(true)
+
+	command := clappertest.Parse(t, registry, "info", "--username", "thatisuday", "--subjects=math", "--subjects", "science", "--category", "student")
+	clappertest.AssertArgs(t, command, map[string]string{"category": "student", "username": "thatisuday", "subjects": "math,science"})
+
+	names := make([]string, 0)
+	for _, occurrence := range command.Occurrences {
+		names = append(names, occurrence.Name)
+	}
+	if want := []string{"username", "subjects", "subjects", "category"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("got occurrences %q, want %q", names, want)
+	}
+
+	// the flags take precedence over the named arguments
+	registry.Commands["info"].AddArg("output", "")
+	command = clappertest.Parse(t, registry, "info", "--output", "dir", "math")
+	clappertest.AssertFlags(t, command, map[string]string{"output": "dir"})
+	clappertest.AssertArgs(t, command, map[string]string{"category": "math", "output": ""})
+
+	result := clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"info", "--category=worker"}})
+	clappertest.AssertError(t, result.Err, clapper.ErrorUnsupportedValue{Name: "category", Value: "worker"})
+
+	help := clappertest.Help(t, registry, "info")
+	if want := "  category, --category "; !strings.Contains(help, want) {
+		t.Errorf("help %q doesn't contain %q", help, want)
+	}
+}
@@ -0,0 +1,299 @@
+package clapper
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Catalog interface provides the translated messages of the errors, help and prompts (see `Registry.Catalog`).
+// The keys of the messages are the keys of `EnglishMessages`, the messages are `fmt` formats with the same arguments
+// (explicit argument indexes like `%[2]s` can reorder them). The descriptions of the commands, flags and arguments
+// are looked up as keys too, so they can be translated by a catalog of the application.
+type Catalog interface {
+
+	// Message returns the message with the key and `true` if the catalog has it.
+	Message(key string) (string, bool)
+}
+
+// Messages type is a `Catalog` holding the messages in a map.
+type Messages map[string]string
+
+// Message method implements `Catalog` interface.
+func (messages Messages) Message(key string) (string, bool) {
+	message, ok := messages[key]
+	return message, ok
+}
+
+// EnglishMessages is the built-in English catalog, the default messages.
+var EnglishMessages = Messages{
+	"error.unknownCommand":    "unknown command %s found in the arguments",
+	"error.unknownFlag":       "unknown flag %s found in the arguments",
+	"error.unsupportedFlag":   "unsupported flag %s found in the arguments",
+	"error.dumpArgs":          "parsed arguments dumped",
+	"error.help":              "help requested",
+	"error.version":           "version printed",
+	"error.missingFlag":       "required flag --%s not found in the arguments",
+	"error.missingArg":        "required argument %s not found in the arguments",
+	"error.unsupportedValue":  "unsupported value %s=%s found in the arguments",
+	"error.flagValue":         "invalid value %s=%s found in the arguments: %v",
+	"error.typedFlagValue":    "invalid %s value %s=%s found in the arguments: %v",
+	"error.argValue":          "invalid argument %s=%s found in the arguments: %v",
+	"error.typedArgValue":     "invalid %s argument %s=%s found in the arguments: %v",
+	"error.misplacedArg":      "argument %s found after the flags in the arguments",
+	"error.ambiguousCommand":  "ambiguous command %[1]s found in the arguments (use -- %[1]s for an argument)",
+	"error.responseFile":      "response file %s: %v",
+	"error.responseFileLine":  "response file %s, line %d: %v",
+	"error.responseFileCycle": "response file %s includes itself",
+	"error.responseFileDepth": "response file %s is nested deeper than %d levels",
+	"error.schemaVersion":     "unsupported schema version %d (supported version is %d)",
+	"error.schemaInvalid":     "invalid schema for command %q: %s",
+	"error.sensitiveFile":     "can't read value of flag %s from file %s: %v",
+	"error.prompt":            "can't prompt value of %s: %v",
+	"error.configFile":        "invalid line %d in configuration file %s",
+	"error.unterminatedQuote": "unterminated %s quote at line %d, column %d",
+	"help.usage":              "Usage:",
+	"help.command":            "[command]",
+	"help.flags":              "[flags]",
+	"help.value":              "<value>",
+	"help.aliases":            "Aliases: %s",
+	"help.commandsHeading":    "Commands:",
+	"help.argumentsHeading":   "Arguments:",
+	"help.flagsHeading":       "Flags:",
+	"help.required":           "(required)",
+	"help.default":            "(default: %s)",
+	"help.env":                "(env: %s)",
+	"help.plugin":             "(plugin %s)",
+	"help.sensitiveFile":      `read %s from the file ("-" for the standard input)`,
+	"help.helpFlag":           "show the help of the command",
+	"help.versionFlag":        "show the version of the program",
+	"shell.builtinsHeading":   "Built-in commands:",
+	"shell.help":              "show the help of the shell or a command",
+	"shell.history":           "show the entered lines",
+	"shell.exit":              "exit the shell",
+	"shell.error":             "error: %s",
+	"shell.historyFile":       "can't write the history file %s: %v",
+	"prompt.select":           "Select [1-%d]",
+	"prompt.invalid":          "invalid value %s",
+	"warning":                 "warning: %s",
+	"deprecated.command":      "command %s is deprecated",
+	"deprecated.flag":         "flag %s is deprecated",
+	"deprecated.replacedBy":   "%s, use %s instead",
+	"deprecated.message":      "%s: %s",
+	"quote.single":            "single",
+	"quote.double":            "double",
+	"hint.help":               "Run '%s --help' for more information.",
+}
+
+// RussianMessages is the built-in Russian catalog.
+var RussianMessages = Messages{
+	"error.unknownCommand":    "неизвестная команда %s в аргументах",
+	"error.unknownFlag":       "неизвестный флаг %s в аргументах",
+	"error.unsupportedFlag":   "неподдерживаемый флаг %s в аргументах",
+	"error.dumpArgs":          "разобранные аргументы выведены",
+	"error.help":              "запрошена справка",
+	"error.version":           "версия выведена",
+	"error.missingFlag":       "обязательный флаг --%s не найден в аргументах",
+	"error.missingArg":        "обязательный аргумент %s не найден в аргументах",
+	"error.unsupportedValue":  "неподдерживаемое значение %s=%s в аргументах",
+	"error.flagValue":         "недопустимое значение %s=%s в аргументах: %v",
+	"error.typedFlagValue":    "недопустимое значение типа %s %s=%s в аргументах: %v",
+	"error.argValue":          "недопустимый аргумент %s=%s в аргументах: %v",
+	"error.typedArgValue":     "недопустимый аргумент типа %s %s=%s в аргументах: %v",
+	"error.misplacedArg":      "аргумент %s найден после флагов в аргументах",
+	"error.ambiguousCommand":  "неоднозначная команда %[1]s в аргументах (используйте -- %[1]s для аргумента)",
+	"error.responseFile":      "файл ответов %s: %v",
+	"error.responseFileLine":  "файл ответов %s, строка %d: %v",
+	"error.responseFileCycle": "файл ответов %s включает сам себя",
+	"error.responseFileDepth": "вложенность файла ответов %s превышает %d уровней",
+	"error.schemaVersion":     "неподдерживаемая версия схемы %d (поддерживается версия %d)",
+	"error.schemaInvalid":     "недопустимая схема команды %q: %s",
+	"error.sensitiveFile":     "не удалось прочитать значение флага %s из файла %s: %v",
+	"error.prompt":            "не удалось запросить значение %s: %v",
+	"error.configFile":        "недопустимая строка %d в файле конфигурации %s",
+	"error.unterminatedQuote": "незакрытая %s кавычка в строке %d, столбец %d",
+	"help.usage":              "Использование:",
+	"help.command":            "[команда]",
+	"help.flags":              "[флаги]",
+	"help.value":              "<значение>",
+	"help.aliases":            "Псевдонимы: %s",
+	"help.commandsHeading":    "Команды:",
+	"help.argumentsHeading":   "Аргументы:",
+	"help.flagsHeading":       "Флаги:",
+	"help.required":           "(обязательный)",
+	"help.default":            "(по умолчанию: %s)",
+	"help.env":                "(переменная окружения: %s)",
+	"help.plugin":             "(плагин %s)",
+	"help.sensitiveFile":      `прочитать %s из файла ("-" для стандартного ввода)`,
+	"help.helpFlag":           "показать справку команды",
+	"help.versionFlag":        "показать версию программы",
+	"shell.builtinsHeading":   "Встроенные команды:",
+	"shell.help":              "показать справку оболочки или команды",
+	"shell.history":           "показать введённые строки",
+	"shell.exit":              "выйти из оболочки",
+	"shell.error":             "ошибка: %s",
+	"shell.historyFile":       "не удалось записать файл истории %s: %v",
+	"prompt.select":           "Выберите [1-%d]",
+	"prompt.invalid":          "недопустимое значение %s",
+	"warning":                 "предупреждение: %s",
+	"deprecated.command":      "команда %s устарела",
+	"deprecated.flag":         "флаг %s устарел",
+	"deprecated.replacedBy":   "%s, используйте %s",
+	"deprecated.message":      "%s: %s",
+	"quote.single":            "одинарная",
+	"quote.double":            "двойная",
+	"hint.help":               "Запустите '%s --help' для получения подробностей.",
+}
+
+// the catalogs by language (the language of a locale, for example `ru` for `ru_RU.UTF-8`)
+var (
+	catalogsMutex sync.RWMutex
+	catalogs      = map[string]Catalog{
+		"en": EnglishMessages,
+		"ru": RussianMessages,
+	}
+)
+
+// RegisterCatalog function adds (or replaces) the catalog of a language (the language of a locale,
+// for example `ru` for `ru_RU.UTF-8`) to the built-in catalogs. It is safe for concurrent use.
+func RegisterCatalog(language string, catalog Catalog) {
+	catalogsMutex.Lock()
+	defer catalogsMutex.Unlock()
+	catalogs[strings.ToLower(language)] = catalog
+}
+
+// LookupCatalog function returns the catalog of a language and `true` if the catalog is registered.
+func LookupCatalog(language string) (Catalog, bool) {
+	catalogsMutex.RLock()
+	defer catalogsMutex.RUnlock()
+	catalog, ok := catalogs[strings.ToLower(language)]
+	return catalog, ok
+}
+
+/*---------------------*/
+
+// return the language of the registry: `Registry.Locale` or the locale of `LC_ALL`, `LC_MESSAGES` or `LANG`
+// environment variable (the first non-empty one), for example `ru` for `ru_RU.UTF-8`
+func (registry *Registry) language() string {
+	locale := registry.Locale
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale != "" {
+			break
+		}
+		locale, _ = registry.lookupEnv(name)
+	}
+
+	if i := strings.IndexAny(locale, "_.@-"); i != -1 {
+		locale = locale[:i]
+	}
+	return strings.ToLower(locale)
+}
+
+// messages of a registry: the catalog of the registry, the catalog of the language and English messages
+type localizer []Catalog
+
+// return the messages of the registry
+func (registry *Registry) messages() localizer {
+	l := make(localizer, 0, 3)
+	if registry.Catalog != nil {
+		l = append(l, registry.Catalog)
+	}
+	if catalog, ok := LookupCatalog(registry.language()); ok {
+		l = append(l, catalog)
+	}
+	return append(l, EnglishMessages)
+}
+
+// format the message with the key
+func (l localizer) format(key string, args ...interface{}) string {
+	for _, catalog := range l {
+		if message, ok := catalog.Message(key); ok {
+			return fmt.Sprintf(message, args...)
+		}
+	}
+	return key
+}
+
+// translate a text (a description) used as a key, the text is returned if no catalog has it
+func (l localizer) text(text string) string {
+	if text == "" {
+		return text
+	}
+	for _, catalog := range l {
+		if message, ok := catalog.Message(text); ok {
+			return message
+		}
+	}
+	return text
+}
+
+// return the translated message of an error (the message of `Error` method for unknown errors)
+func (l localizer) errorMessage(err error) string {
+	switch e := err.(type) {
+	case ErrorUnknownCommand:
+		return l.format("error.unknownCommand", e.Name)
+	case ErrorUnknownFlag:
+		return l.format("error.unknownFlag", e.Name)
+	case ErrorUnsupportedFlag:
+		return l.format("error.unsupportedFlag", e.Name)
+	case ErrorDumpArgs:
+		return l.format("error.dumpArgs")
+	case ErrorHelp:
+		return l.format("error.help")
+	case ErrorVersion:
+		return l.format("error.version")
+	case ErrorMissingFlag:
+		return l.format("error.missingFlag", e.Name)
+	case ErrorMissingArg:
+		return l.format("error.missingArg", e.Name)
+	case ErrorUnsupportedValue:
+		return l.format("error.unsupportedValue", e.Name, e.Value)
+	case ErrorFlagValue:
+		cause := RedactedValue
+		if !e.Sensitive {
+			cause = l.errorMessage(e.Err)
+		}
+		if e.Type != "" {
+			return l.format("error.typedFlagValue", e.Type, e.Name, e.Value, cause)
+		}
+		return l.format("error.flagValue", e.Name, e.Value, cause)
+	case ErrorArgValue:
+		if e.Type != "" {
+			return l.format("error.typedArgValue", e.Type, e.Name, e.Value, l.errorMessage(e.Err))
+		}
+		return l.format("error.argValue", e.Name, e.Value, l.errorMessage(e.Err))
+	case ErrorMisplacedArg:
+		return l.format("error.misplacedArg", e.Value)
+	case ErrorAmbiguousCommand:
+		return l.format("error.ambiguousCommand", e.Name)
+	case ErrorResponseFile:
+		if e.Line > 0 {
+			return l.format("error.responseFileLine", e.File, e.Line, l.errorMessage(e.Err))
+		}
+		return l.format("error.responseFile", e.File, l.errorMessage(e.Err))
+	case ErrorResponseFileCycle:
+		return l.format("error.responseFileCycle", e.File)
+	case ErrorResponseFileDepth:
+		return l.format("error.responseFileDepth", e.File, MaxResponseFileDepth)
+	case ErrorSchemaVersion:
+		return l.format("error.schemaVersion", e.Version, SchemaVersion)
+	case ErrorSchemaInvalid:
+		return l.format("error.schemaInvalid", e.Command, e.Reason)
+	case ErrorSensitiveFile:
+		return l.format("error.sensitiveFile", e.Name, e.File, l.errorMessage(e.Err))
+	case ErrorPrompt:
+		return l.format("error.prompt", e.Name, l.errorMessage(e.Err))
+	case ErrorConfigFile:
+		return l.format("error.configFile", e.Line, e.File)
+	case ErrorUnterminatedQuote:
+		return l.format("error.unterminatedQuote", l.format("quote."+e.kind()), e.Line, e.Column)
+	}
+
+	return err.Error()
+}
+
+// ErrorMessage method returns the message of an error of the package in the language of the registry
+// (see `Registry.Catalog`), the message of `Error` method for other errors.
+func (registry *Registry) ErrorMessage(err error) string {
+	return registry.messages().errorMessage(err)
+}
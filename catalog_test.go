@@ -0,0 +1,146 @@
+package clapper_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/msaf1980/clapper"
+	"github.com/msaf1980/clapper/clappertest"
+)
+
+// the messages of the tests are English, the help is not wrapped and the ordering is permuted
+// regardless of the environment
+func TestMain(m *testing.M) {
+	os.Setenv("LC_ALL", "C")
+	os.Unsetenv("COLUMNS")
+	os.Unsetenv(clapper.PosixlyCorrectEnv)
+	os.Exit(m.Run())
+}
+
+// test the messages of the errors
+func TestErrorMessage(t *testing.T) {
+	registry := clapper.NewRegistry()
+	registry.Locale = "en"
+
+	cause := errors.New("cause")
+	errs := []error{
+		clapper.ErrorUnknownCommand{Name: "x"},
+		clapper.ErrorUnknownFlag{Name: "--x"},
+		clapper.ErrorUnsupportedFlag{Name: "---x"},
+		clapper.ErrorDumpArgs{},
+		clapper.ErrorHelp{Command: "x"},
+		clapper.ErrorMissingFlag{Name: "x"},
+		clapper.ErrorMissingArg{Name: "x"},
+		clapper.ErrorUnsupportedValue{Name: "x", Value: "y"},
+		clapper.ErrorFlagValue{Name: "x", Value: "y", Err: cause},
+		clapper.ErrorFlagValue{Name: "x", Type: "level", Value: "y", Err: cause},
+		clapper.ErrorArgValue{Name: "x", Value: "y", Err: cause},
+		clapper.ErrorArgValue{Name: "x", Type: "level", Value: "y", Err: cause},
+		clapper.ErrorMisplacedArg{Value: "x"},
+		clapper.ErrorAmbiguousCommand{Name: "x"},
+		clapper.ErrorResponseFile{File: "x", Err: cause},
+		clapper.ErrorResponseFile{File: "x", Line: 2, Err: cause},
+		clapper.ErrorResponseFileCycle{File: "x"},
+		clapper.ErrorResponseFileDepth{File: "x"},
+		clapper.ErrorSchemaVersion{Version: 9},
+		clapper.ErrorSchemaInvalid{Command: "x", Reason: "y"},
+		clapper.ErrorSensitiveFile{Name: "x", File: "y", Err: cause},
+		clapper.ErrorPrompt{Name: "x", Err: cause},
+		clapper.ErrorConfigFile{File: "x", Line: 2},
+		clapper.ErrorUnterminatedQuote{Quote: '\'', Line: 1, Column: 2},
+		cause,
+	}
+	for _, err := range errs {
+		if got, want := registry.ErrorMessage(err), err.Error(); got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+
+	registry.Locale = "ru"
+	for _, err := range errs[:len(errs)-1] {
+		if got := registry.ErrorMessage(err); got == err.Error() || strings.Contains(got, "%!") {
+			t.Errorf("got %q for %T", got, err)
+		}
+	}
+	if got, want := registry.ErrorMessage(clapper.ErrorUnknownCommand{Name: "x"}), "неизвестная команда x в аргументах"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// the nested errors are translated
+	err := clapper.ErrorResponseFile{File: "x", Line: 1, Err: clapper.ErrorUnterminatedQuote{Quote: '"', Line: 1, Column: 2}}
+	if got, want := registry.ErrorMessage(err), "файл ответов x, строка 1: незакрытая двойная кавычка в строке 1, столбец 2"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// test the built-in catalogs
+func TestCatalogs(t *testing.T) {
+	for _, language := range []string{"en", "ru"} {
+		catalog, ok := clapper.LookupCatalog(language)
+		if !ok {
+			t.Fatalf("catalog %s not found", language)
+		}
+		for key := range clapper.EnglishMessages {
+			if _, ok := catalog.Message(key); !ok {
+				t.Errorf("message %s not found in %s catalog", key, language)
+			}
+		}
+	}
+}
+
+// test the registered catalog of a language
+func TestRegisterCatalog(t *testing.T) {
+	clapper.RegisterCatalog("XX", clapper.Messages{"help.usage": "Xx:"})
+	if catalog, ok := clapper.LookupCatalog("xx"); !ok {
+		t.Error("catalog xx not found")
+	} else if message, _ := catalog.Message("help.usage"); message != "Xx:" {
+		t.Errorf("got message %q, want %q", message, "Xx:")
+	}
+
+	registry := newDemoRegistry(true)
+	registry.Locale = "xx_XX.UTF-8"
+	if help := clappertest.Help(t, registry, ""); !strings.HasPrefix(help, "Xx:") {
+		t.Errorf("help %q doesn't start with %q", help, "Xx:")
+	}
+}
+
+// test the selection of the language and translated help
+func TestLocale(t *testing.T) {
+	env := map[string]string{"LANG": "en_US.UTF-8", "LC_MESSAGES": "ru_RU.UTF-8"}
+	registry := newDemoRegistry(true)
+	registry.LookupEnv = func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+	registry.Catalog = clapper.Messages{"show information": "показать информацию"}
+	registry.Commands["info"].SetDescription("show information")
+
+	help := clappertest.Help(t, registry, "")
+	for _, want := range []string{"Использование: demo [команда] [флаги] [output]", "Команды:", "  info    показать информацию"} {
+		if !strings.Contains(help, want) {
+			t.Errorf("help %q doesn't contain %q", help, want)
+		}
+	}
+
+	env["LC_ALL"] = "C"
+	help = clappertest.Help(t, registry, "")
+	if want := "Usage: demo [command] [flags] [output]"; !strings.Contains(help, want) {
+		t.Errorf("help %q doesn't contain %q", help, want)
+	}
+
+	// the prompt
+	registry = newDemoRegistry(true)
+	registry.Locale = "ru"
+	var out strings.Builder
+	registry.Prompter = &clapper.Prompter{In: strings.NewReader("worker\n1\n"), Out: &out}
+	registry.Commands["info"].Args["category"].SetRequired(true)
+	registry.Commands["info"].Args["category"].DefaultValue = ""
+	if _, err := registry.Parse([]string{"info"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Выберите [1-6]: недопустимое значение worker\n"; !strings.Contains(out.String(), want) {
+		t.Errorf("prompt %q doesn't contain %q", out.String(), want)
+	}
+}
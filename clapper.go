@@ -26,7 +26,12 @@
 package clapper
 
 import (
+	"flag"
 	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -35,22 +40,33 @@ import (
 ***********************************************/
 
 // format command-line argument values
-func formatCommandValues(values []string) (formatted []string) {
+// `indexes` holds the index of the command-line argument value of each formatted value
+func formatCommandValues(values []string) (formatted []string, indexes []int) {
 
 	formatted = make([]string, 0)
+	indexes = make([]int, 0)
 
 	// split a value by `=`
-	for _, value := range values {
-		if isFlag(value) {
+	for i, value := range values {
+		if isEndOfFlags(value) {
+			// values after `--` are kept as is
+			for j := i; j < len(values); j++ {
+				formatted = append(formatted, values[j])
+				indexes = append(indexes, j)
+			}
+			break
+		} else if isFlag(value) {
 			parts := strings.Split(value, "=")
 
 			for _, part := range parts {
 				if strings.Trim(part, " ") != "" {
 					formatted = append(formatted, part)
+					indexes = append(indexes, i)
 				}
 			}
 		} else {
 			formatted = append(formatted, value)
+			indexes = append(indexes, i)
 		}
 	}
 
@@ -62,6 +78,11 @@ func isFlag(value string) bool {
 	return len(value) >= 2 && strings.HasPrefix(value, "-")
 }
 
+// check if value is the end of flags marker `--`
+func isEndOfFlags(value string) bool {
+	return value == "--"
+}
+
 // check if value is a short flag
 func isShortFlag(value string) bool {
 	return isFlag(value) && len(value) == 2 && !strings.HasPrefix(value, "--")
@@ -70,7 +91,7 @@ func isShortFlag(value string) bool {
 // check if value starts with `--no-` prefix
 func isInvertedFlag(value string) (bool, string) {
 	if isFlag(value) && strings.HasPrefix(value, "--no-") {
-		return true, strings.TrimLeft(value, "--no-") // trim `--no-` prefix
+		return true, strings.TrimPrefix(value, "--no-") // trim `--no-` prefix
 	}
 
 	return false, strings.TrimLeft(value, "--")
@@ -97,64 +118,75 @@ func isUnsupportedFlag(value string) bool {
 // check if value ends with `...` sufix
 func isVariadicArgument(value string) (bool, string) {
 	if !isFlag(value) && strings.HasSuffix(value, "...") {
-		return true, strings.TrimRight(value, "...") // trim `...` suffix
+		return true, strings.TrimSuffix(value, "...") // trim `...` suffix
 	}
 
 	return false, ""
 }
 
-// check if values corresponds to the root command
-func isRootCommand(values []string, registry Registry) bool {
+// return next value and remaining values of a slice of strings,
+// `ok` is false if the slice is exhausted (a value can be an empty string)
+func nextValue(slice []string) (v string, newSlice []string, ok bool) {
 
-	// FALSE: if the root command is not registered
-	if _, ok := registry[""]; !ok {
-		return false
-	}
-
-	// TRUE: if all `values` are empty or the first `value` is a flag
-	if len(values) == 0 || isFlag(values[0]) {
-		return true
+	if len(slice) == 0 {
+		return "", make([]string, 0), false
 	}
 
-	// get root `CommandConfig` value from the registry
-	rootCommandConfig := registry[""]
-
-	// TRUE: if the first value is not a registered command
-	// and some arguments are registered for the root command
-	if _, ok := registry[values[0]]; len(rootCommandConfig.Args) > 0 && !ok {
-		return true
-	}
+	return slice[0], slice[1:], true
+}
 
-	return false
+// trim whitespaces from a value
+func trimWhitespaces(value string) string {
+	return strings.Trim(value, "")
 }
 
-// return next value and remaining values of a slice of strings
-func nextValue(slice []string) (v string, newSlice []string) {
+// remove whitespaces from a value
+func removeWhitespaces(value string) string {
+	return strings.ReplaceAll(value, " ", "")
+}
 
-	if len(slice) == 0 {
-		v, newSlice = "", make([]string, 0)
-		return
+// build a set of valid values
+func makeValidVals(validVals []string) (valid map[string]bool, order []string) {
+	if len(validVals) == 0 {
+		return nil, nil
 	}
 
-	v = slice[0]
-
-	if len(slice) > 1 {
-		newSlice = slice[1:]
-	} else {
-		newSlice = make([]string, 0)
+	valid = make(map[string]bool)
+	order = make([]string, 0, len(validVals))
+	for _, v := range validVals {
+		if !valid[v] {
+			valid[v] = true
+			order = append(order, v)
+		}
 	}
 
 	return
 }
 
-// trim whitespaces from a value
-func trimWhitespaces(value string) string {
-	return strings.Trim(value, "")
-}
+// return valid values in registration order (values added directly to the map are appended sorted)
+func orderedValidVals(valid map[string]bool, order []string) []string {
+	if len(valid) == 0 {
+		return nil
+	}
 
-// remove whitespaces from a value
-func removeWhitespaces(value string) string {
-	return strings.ReplaceAll(value, " ", "")
+	list := make([]string, 0, len(valid))
+	seen := make(map[string]bool)
+	for _, v := range order {
+		if valid[v] && !seen[v] {
+			seen[v] = true
+			list = append(list, v)
+		}
+	}
+
+	extra := make([]string, 0)
+	for v, ok := range valid {
+		if ok && !seen[v] {
+			extra = append(extra, v)
+		}
+	}
+	sort.Strings(extra)
+
+	return append(list, extra...)
 }
 
 /***********************************************/
@@ -186,6 +218,32 @@ func (e ErrorUnsupportedFlag) Error() string {
 	return fmt.Sprintf("unsupported flag %s found in the arguments", e.Name)
 }
 
+// ErrorDumpArgs is returned by `Registry.Parse` when the hidden `--dump-args` flag is found in the arguments.
+// The parsed arguments are written to `Registry.Stdout` in JSON format before the error is returned.
+type ErrorDumpArgs struct{}
+
+func (e ErrorDumpArgs) Error() string {
+	return "parsed arguments dumped"
+}
+
+// ErrorMissingFlag represents an error when command-line arguments don't contain a required flag.
+type ErrorMissingFlag struct {
+	Name string
+}
+
+func (e ErrorMissingFlag) Error() string {
+	return fmt.Sprintf("required flag --%s not found in the arguments", e.Name)
+}
+
+// ErrorMissingArg represents an error when command-line arguments don't contain a required argument.
+type ErrorMissingArg struct {
+	Name string
+}
+
+func (e ErrorMissingArg) Error() string {
+	return fmt.Sprintf("required argument %s not found in the arguments", e.Name)
+}
+
 // ErrorUnsupportedValue represents an error when command-line arguments contain an unsupported value.
 type ErrorUnsupportedValue struct {
 	Name  string
@@ -198,21 +256,126 @@ func (e ErrorUnsupportedValue) Error() string {
 
 /*---------------------*/
 
-// Registry holds the configuration of the registered commands.
-type Registry map[string]*CommandConfig
+// DumpArgsFlag is the name of the hidden flag which dumps the parsed arguments (see `Registry.DumpArgs`).
+const DumpArgsFlag = "dump-args"
+
+// Registry holds the configuration of the registered commands and the options of the parser.
+// Registry was a `map[string]*CommandConfig` type before the options were added,
+// the commands are in the `Commands` field now and `NewRegistry` returns a pointer.
+type Registry struct {
+
+	// name of the program in the help text (base name of `os.Args[0]` if empty)
+	Name string
+
+	// registered commands (and command aliases) by name
+	Commands map[string]*CommandConfig
+
+	// if true, the hidden `--dump-args` flag is accepted by all commands (unless a command registers its own `dump-args` flag)
+	// and `Parse` writes the parsed arguments to `Stdout` in JSON format and returns `ErrorDumpArgs` error
+	DumpArgs bool
+
+	// if not nil, missing values of the required flags and arguments are asked interactively (see `Prompter`)
+	Prompter *Prompter
+
+	// function returning the value of an environment variable (`os.LookupEnv` if nil, see `FlagCommand.SetEnvVar`)
+	LookupEnv func(name string) (string, bool)
+
+	// configurations providing the values of the flags missing in the command-line arguments and environment,
+	// the first configuration containing the flag wins
+	Configs []ConfigSource
+
+	// placement of the flags and arguments (see `Ordering`), commands can override it (see `CommandConfig.SetOrdering`)
+	Ordering Ordering
+
+	// if true, the command name is the first value after the leading flags, they are the global flags of the root command
+	// (a leading flag takes the next value only if it is a non-boolean flag, or `--<flag>=<value>` is used);
+	// the flags of the root command which are not flags of the command are added to the parsed command
+	// with their default values
+	SkipLeadingFlags bool
+
+	// resolution of a value which is a command name and a value of an argument of the root command (see `OverlapPolicy`)
+	Overlap OverlapPolicy
+
+	// if true, unregistered flags are collected in `CommandParsed.Unknown` instead of returning `ErrorUnknownFlag` error,
+	// commands can enable it (see `CommandConfig.SetAllowUnknownFlags`)
+	AllowUnknownFlags bool
+
+	// alternative syntaxes of the flags accepted in addition to the getopt(3) syntax,
+	// for example `SlashSyntax` (the first syntax accepting a value wins)
+	Syntaxes []FlagSyntax
+
+	// if true, an unregistered command name is resolved to an executable `<Name>-<command>` plugin
+	// in `PluginDirs` or `PATH` (see `Registry.RunPlugin`)
+	Plugins bool
+
+	// directories searched for the plugins before `PATH`
+	PluginDirs []string
+
+	// if true, `--help` and `-h` flags are accepted by all commands (unless a command registers them)
+	// and `Parse` writes the help of the command to `Stdout` and returns `ErrorHelp` error
+	HelpFlags bool
+
+	// if true, `@path` values are replaced with the arguments read from the file `path` (see `ExpandResponseFiles`)
+	ResponseFiles bool
+
+	// language of the messages, for example `ru` (the locale of `LC_ALL`, `LC_MESSAGES` or `LANG` environment variable if empty)
+	Locale string
+
+	// catalog of the translated messages and descriptions looked up before the built-in catalog of the language
+	// (see `RegisterCatalog`)
+	Catalog Catalog
+
+	// if true, deprecation warnings are written to `Stderr` (they are always collected in `CommandParsed.Warnings`)
+	PrintWarnings bool
+
+	// use of the colors in the help, the error messages and the warnings (`ColorAuto` by default)
+	Color ColorMode
+
+	// styles of the colored output (`DefaultTheme` if nil)
+	Theme *Theme
+
+	// width of the help lines, the width of the terminal or `COLUMNS` environment variable if 0
+	// (the descriptions are not wrapped if the width is unknown)
+	Width int
+
+	// writer for the output of the registry (`os.Stdout` if nil)
+	Stdout io.Writer
+
+	// writer for the diagnostic messages of the registry (`os.Stderr` if nil)
+	Stderr io.Writer
+
+	// reader for the values read from the standard input (`os.Stdin` if nil)
+	Stdin io.Reader
+}
+
+// return the writer for the output of the registry
+func (registry *Registry) stdout() io.Writer {
+	if registry.Stdout == nil {
+		return os.Stdout
+	}
+	return registry.Stdout
+}
+
+// return the reader for the values read from the standard input of the registry
+func (registry *Registry) stdin() io.Reader {
+	if registry.Stdin == nil {
+		return os.Stdin
+	}
+	return registry.Stdin
+}
 
 // Register method registers a command.
 // The "name" argument should be a simple string.
 // If "name" is an empty string, it is considered as a root command.
 // If a command is already registered, the registered `*CommandConfig` object is returned.
 // If the command is already registered, second return value will be `true`.
-func (registry Registry) Register(name string) (*CommandConfig, bool) {
+func (registry *Registry) Register(name string) (*CommandConfig, bool) {
 
 	// remove all whitespaces
 	commandName := removeWhitespaces(name)
 
 	// check if command is already registered, if found, return existing entry
-	if _commandConfig, ok := registry[commandName]; ok {
+	if _commandConfig, ok := registry.Commands[commandName]; ok {
 		return _commandConfig, true
 	}
 
@@ -226,15 +389,80 @@ func (registry Registry) Register(name string) (*CommandConfig, bool) {
 	}
 
 	// add entry to the registry
-	registry[commandName] = commandConfig
+	registry.Commands[commandName] = commandConfig
 
 	return commandConfig, false
 }
 
+// RegisterAlias method registers an alternative name for a registered command.
+// The "name" argument is the name of the registered command and "alias" is the alternative name.
+// Parsing the alias produces a `CommandParsed` with the name of the original command.
+// If the command with "name" is not registered, nil is returned.
+// If "alias" is already registered as a command or an alias, the registered `*CommandConfig` object is returned
+// and second return value will be `true`.
+func (registry *Registry) RegisterAlias(name string, alias string) (*CommandConfig, bool) {
+
+	// remove all whitespaces
+	commandName := removeWhitespaces(name)
+	aliasName := removeWhitespaces(alias)
+
+	commandConfig, ok := registry.Commands[commandName]
+	if !ok {
+		return nil, false
+	}
+
+	// check if alias is already registered, if found, return existing entry
+	if _commandConfig, ok := registry.Commands[aliasName]; ok {
+		return _commandConfig, true
+	}
+
+	// add alias entry to the registry
+	registry.Commands[aliasName] = commandConfig
+	commandConfig.Aliases = append(commandConfig.Aliases, aliasName)
+
+	return commandConfig, false
+}
+
+// CommandNames method returns the names of the registered commands (without aliases) in sorted order.
+// The root command, if registered, is the first name.
+func (registry *Registry) CommandNames() []string {
+	names := make([]string, 0, len(registry.Commands))
+	for name, commandConfig := range registry.Commands {
+		if name == commandConfig.Name {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
 // Parse method parses command-line arguments and returns an appropriate "*CommandConfig" object registered in the registry.
-// If command is not registered, it return `ErrorUnknownCommand` error.
-// If there is an error parsing a flag, it can return an `ErrorUnknownFlag` or `ErrorUnsupportedFlag` error.
-func (registry Registry) Parse(values []string) (*CommandParsed, error) {
+// The command name is the first value unless it is a flag or `--` or an argument of the root command
+// (see `Registry.SkipLeadingFlags` and `Registry.Overlap`), `./<value>` or `-- <value>` is an argument of the root command.
+// If command is not registered, it return `ErrorUnknownCommand` error
+// (or a plugin command with the values after the command name in `CommandParsed.Passthrough`, see `Registry.Plugins`).
+// If there is an error parsing a flag, it can return an `ErrorUnknownFlag` or `ErrorUnsupportedFlag` error
+// (unknown flags can be collected in `CommandParsed.Unknown` instead, see `Registry.AllowUnknownFlags`).
+// If response files are enabled and can't be expanded, it returns `ErrorResponseFile` error.
+// Values of the flags missing in the arguments are looked up in the environment variables and `Registry.Configs`.
+// If a required flag or argument is missing (and can't be prompted), it returns `ErrorMissingFlag` or `ErrorMissingArg` error
+// (`ErrorPrompt` error if the prompter fails to read the value).
+// If a bound `flag.Value` rejects the value of a flag (see `FlagCommand.SetFlagValue`), it returns `ErrorFlagValue` error
+// (`ErrorArgValue` error for a value of an argument, see `ArgCommand.SetArgValue`).
+// Values after the `--` marker are not processed as flags. They are bound to the free arguments,
+// the rest of them is stored in `CommandParsed.Passthrough`.
+// Parse doesn't modify the registry, but the registry must not be modified during concurrent calls,
+// use `Registry.Compile` to get a parser which is safe for concurrent use.
+func (registry *Registry) Parse(values []string) (*CommandParsed, error) {
+
+	// expand `@path` values
+	if registry.ResponseFiles {
+		var err error
+		if values, err = ExpandResponseFiles(values); err != nil {
+			return nil, err
+		}
+	}
 
 	// command name
 	var commandName string
@@ -242,18 +470,79 @@ func (registry Registry) Parse(values []string) (*CommandParsed, error) {
 	// command-line argument values to process
 	valuesToProcess := values
 
-	// check if command is a root command
-	if isRootCommand(values, registry) {
-		commandName = "" // root command name
+	// index of the command name in `values` (-1 for the root command)
+	commandAt := -1
+
+	// index of the end of the values to process in `values`
+	end := len(values)
+
+	// index of the values after the command name which are only arguments (-1 if none)
+	restStart := -1
+
+	// find the command name (see `Registry.resolveCommand`)
+	if registry.Ordering == OrderFlagsBeforeCommand {
+		// the flags are before the command name
+		if i := registry.commandIndex(values); i != -1 {
+			commandName, valuesToProcess, end, restStart, commandAt = values[i], values[:i], i, i+1, i
+		}
 	} else {
-		commandName, valuesToProcess = nextValue(values)
+		var err error
+		if commandName, commandAt, err = registry.resolveCommand(values); err != nil {
+			return nil, err
+		}
+		if commandAt != -1 {
+			valuesToProcess = append(append(make([]string, 0, len(values)-1), values[:commandAt]...), values[commandAt+1:]...)
+		}
+	}
+
+	// resolve an unregistered command name to a plugin
+	if path := registry.lookupPlugin(commandName); path != "" {
+		return pluginCommand(commandName, path, valuesToProcess), nil
+	}
+
+	// convert the flags of the alternative syntaxes
+	// (the leading flags before the command name are the flags of the root command, see `Registry.SkipLeadingFlags`)
+	if syntaxCommandConfig, ok := registry.Commands[commandName]; ok {
+		if replacement, ok := registry.Commands[syntaxCommandConfig.ReplacedBy]; ok && syntaxCommandConfig.ReplacedBy != "" {
+			syntaxCommandConfig = replacement
+		}
+		if commandAt > 0 && registry.Ordering != OrderFlagsBeforeCommand {
+			canonical := make([]string, 0, len(valuesToProcess))
+			canonical = append(canonical, registry.canonicalFlags(registry.Commands[""], valuesToProcess[:commandAt])...)
+			valuesToProcess = append(canonical, registry.canonicalFlags(syntaxCommandConfig, valuesToProcess[commandAt:])...)
+		} else {
+			valuesToProcess = registry.canonicalFlags(syntaxCommandConfig, valuesToProcess)
+		}
 	}
 
 	// format command-line argument values
-	valuesToProcess = formatCommandValues(valuesToProcess)
+	valuesToProcess, indexes := formatCommandValues(valuesToProcess)
+
+	// return the index (in `values`) of a formatted value
+	formattedCount := len(valuesToProcess)
+	valueIndex := func(i int) int {
+		if commandAt != -1 && indexes[i] >= commandAt {
+			return indexes[i] + 1
+		}
+		return indexes[i]
+	}
+
+	// return the index (in `values`) of the last value taken from `valuesToProcess`
+	lastIndex := func() int {
+		return valueIndex(formattedCount - len(valuesToProcess) - 1)
+	}
+
+	// placement of the flags and arguments
+	ordering := registry.ordering(registry.Commands[commandName])
 
 	// check for invalid flag structure
+	// (the values after the first argument are checked during processing with the ordering stopping there,
+	// the unsupported flags are collected as unknown flags if they are allowed)
 	for _, val := range valuesToProcess {
+		if isEndOfFlags(val) || (ordering != OrderPermute && ordering != OrderFlagsAfterArgs) ||
+			registry.allowUnknownFlags(registry.Commands[commandName]) {
+			break
+		}
 		if isFlag(val) && isUnsupportedFlag(val) {
 			return nil, ErrorUnsupportedFlag{val}
 		}
@@ -261,118 +550,273 @@ func (registry Registry) Parse(values []string) (*CommandParsed, error) {
 
 	// get `CommandConfig` object from the registry
 	// if command is not registered, return `ErrorUnknownCommand` error
-	commandConfig, ok := registry[commandName]
+	commandConfig, ok := registry.Commands[commandName]
 	if !ok {
 		return nil, ErrorUnknownCommand{commandName}
 	}
 
 	store := &CommandParsed{
-		Name:  commandConfig.Name,
-		Flags: make(map[string]*Flag),
-		Args:  make(map[string]*Arg),
+		Flags:       make(map[string]*Flag),
+		Args:        make(map[string]*Arg),
+		ArgNames:    append(make([]string, 0, len(commandConfig.ArgNames)), commandConfig.ArgNames...),
+		Occurrences: make([]*Occurrence, 0),
+		Passthrough: make([]string, 0),
+		Unknown:     make([]string, 0),
+		Warnings:    make([]string, 0),
 	}
 
+	// check for a deprecated command
+	commandConfig = registry.replaceCommand(commandConfig, store)
+	store.Name = commandConfig.Name
+
+	// if the hidden `--dump-args` flag is found
+	dumpArgs := false
+
+	// positional values matched with the arguments after the processing of the flags (see `CommandConfig.matchArgs`)
+	positionals := make([]positionalValue, 0)
+
+	// add the values to the positional values, the values which are not bound are stored in `CommandParsed.Passthrough`
+	bindValues := func(start int, end int) {
+		for index := start; index < end; index++ {
+			positionals = append(positionals, positionalValue{values[index], index, true})
+		}
+	}
+
+	// all values after the command name are arguments
+	if ordering == OrderFlagsBeforeCommand && commandName != "" && registry.Ordering != OrderFlagsBeforeCommand {
+		leading := 0
+		for leading < len(indexes) && indexes[leading] < commandAt {
+			leading++
+		}
+		valuesToProcess, end, restStart = valuesToProcess[:leading], commandAt, commandAt+1
+	}
+
+	// if a flag is found (for `OrderFlagsAfterArgs` ordering)
+	flagFound := false
+
+	// the flags before the command name are the global flags of the root command (see `Registry.SkipLeadingFlags`)
+	rootCommandConfig, globalFlags := registry.Commands[""]
+	globalFlags = globalFlags && registry.SkipLeadingFlags && commandConfig.Name != "" && registry.Ordering != OrderFlagsBeforeCommand
+
 	// process all command-line arguments (except command name)
 	for {
 
 		// get current command-line argument value
-		var value string
-		value, valuesToProcess = nextValue(valuesToProcess)
+		value, nextValuesToProcess, ok := nextValue(valuesToProcess)
+
+		// if all values are processed, break the loop
+		if !ok {
+			break
+		}
+		valuesToProcess = nextValuesToProcess
+
+		// command of the flag
+		flagConfig := commandConfig
+		if globalFlags && lastIndex() < commandAt {
+			flagConfig = rootCommandConfig
+		}
 
-		// if `value` is empty, break the loop
-		if len(value) == 0 {
+		// bind all values after `--` to the arguments
+		if isEndOfFlags(value) {
+			bindValues(lastIndex()+1, end)
 			break
 		}
 
+		// bind the first argument and all values after it to the arguments
+		if !isFlag(value) && (ordering == OrderRequire || ordering == OrderFlagsBeforeCommand) {
+			bindValues(lastIndex(), end)
+			break
+		}
+
+		// check for the hidden `--dump-args` flag
+		if _, ok := commandConfig.Flags[DumpArgsFlag]; registry.DumpArgs && !ok && value == "--"+DumpArgsFlag {
+			dumpArgs = true
+			continue
+		}
+
+		// write the help of the command for the `--help` and `-h` flags
+		if registry.isHelpFlag(commandConfig, value) {
+			if err := registry.WriteHelp(registry.stdout(), commandConfig.Name); err != nil {
+				return nil, err
+			}
+			return nil, ErrorHelp{commandConfig.Name}
+		}
+
+		// write the version of the program for the `--version` and `-V` flags (`--version=json` for JSON format)
+		if isVersionFlag(flagConfig, value) {
+			asJSON := false
+			if len(valuesToProcess) > 0 && valueIndex(formattedCount-len(valuesToProcess)) == lastIndex() {
+				if format := valuesToProcess[0]; format != "json" {
+					return nil, ErrorUnsupportedValue{"version", format}
+				}
+				asJSON = true
+			}
+			if err := registry.writeVersion(registry.stdout(), flagConfig.Version, asJSON); err != nil {
+				return nil, err
+			}
+			return nil, ErrorVersion{}
+		}
+
 		// check if `value` is a `flag` or an `argument`
 		if isFlag(value) {
+			// an unsupported flag is an unknown flag if they are allowed
+			unsupported := isUnsupportedFlag(value)
+			if unsupported && !registry.allowUnknownFlags(flagConfig) {
+				return nil, ErrorUnsupportedFlag{value}
+			}
+			flagFound = true
 
 			// trim `-` characters from the `value`
 			name := strings.TrimLeft(value, "-")
 
-			// get flag object stored in the `commandConfig`
+			// index of the flag in `values`
+			index := lastIndex()
+
+			// get flag object stored in the `flagConfig`
 			var flag *FlagCommand
 
-			// check if flag is short or long
-			if isShortFlag(value) {
-				// get long flag name
-				flagName, ok := commandConfig.flagsShort[name]
-				if !ok {
-					return nil, ErrorUnknownFlag{value}
-				}
+			// if the value of a sensitive flag is read from a file (`--<flag>-file <path>`)
+			fromFile := false
 
+			// check if flag is short or long (an unsupported flag is not looked up)
+			if !unsupported && isShortFlag(value) {
 				// get long flag name
-				flag = commandConfig.Flags[flagName]
-			} else {
+				if flagName, ok := flagConfig.flagsShort[name]; ok {
+					flag = flagConfig.Flags[flagName]
+				}
+			} else if !unsupported {
 
 				// check if a flag is an inverted flag
 				if ok, flagName := isInvertedFlag(value); ok {
-					flag, ok = commandConfig.Flags[flagName]
-					if !ok {
-						return nil, ErrorUnknownFlag{value}
-					}
+					flag = flagConfig.Flags[flagName]
 				} else {
 					// flag should not registered as an inverted flag
-					flag, ok = commandConfig.Flags[flagName]
-					if !ok || flag.IsInverted {
-						return nil, ErrorUnknownFlag{value}
+					flag, ok = flagConfig.Flags[flagName]
+					if !ok {
+						flag = flagConfig.sensitiveFileFlag(flagName)
+						fromFile = flag != nil
 					}
+					if flag != nil && flag.IsInverted {
+						flag = nil
+					}
+				}
+			}
+
+			// set a named argument (`--<arg> <value>`)
+			if arg := flagConfig.namedArg(value); flag == nil && !unsupported && arg != nil && flagConfig == commandConfig {
+				if nextValue, nextValuesToProcess, ok := nextValue(valuesToProcess); ok && !isFlag(nextValue) {
+					if err := arg.bind(store, nextValue); err != nil {
+						return nil, err
+					}
+					valuesToProcess = nextValuesToProcess
+					store.addArgOccurrence(arg.Name, nextValue, index, values)
 				}
+				continue
 			}
 
+			// collect an unknown flag with its value
+			if flag == nil {
+				if !registry.allowUnknownFlags(flagConfig) {
+					return nil, ErrorUnknownFlag{value}
+				}
+
+				// skip the parts of `--<flag>=<value>`
+				for len(valuesToProcess) > 0 && valueIndex(formattedCount-len(valuesToProcess)) == index {
+					valuesToProcess = valuesToProcess[1:]
+				}
+				store.Unknown = append(store.Unknown, values[index])
+
+				// the next value is the value of the flag unless it is a flag
+				if nextValue := valuesToProcess; !strings.Contains(values[index], "=") && len(nextValue) > 0 &&
+					!isFlag(nextValue[0]) && !isEndOfFlags(nextValue[0]) {
+					valuesToProcess = valuesToProcess[1:]
+					store.Unknown = append(store.Unknown, values[lastIndex()])
+				}
+				continue
+			}
+
+			// check for a deprecated flag
+			flag = registry.replaceFlag(flagConfig, flag, store)
+
 			// set flag value
-			if flag.IsBoolean {
+			if fromFile {
+				if path, nextValuesToProcess, ok := nextValue(valuesToProcess); ok && !isFlag(path) {
+					fileValue, err := registry.readSensitiveFile(flag, path)
+					if err != nil {
+						return nil, err
+					}
+					if !flag.Validate(fileValue) {
+						return nil, ErrorUnsupportedValue{flag.Name, RedactedValue}
+					}
+					store.Flags[flag.Name] = flag.StoreFrom(fileValue, SourceFile, path)
+					valuesToProcess = nextValuesToProcess
+					store.addFlagOccurrence(flag, fileValue, index, values)
+				}
+			} else if flag.IsBoolean {
 				if flag.IsInverted {
 					store.Flags[flag.Name] = flag.Store("false") // if flag is an inverted flag, its value will be `false`
 				} else {
 					store.Flags[flag.Name] = flag.Store("true")
 				}
+
+				// a bound boolean value accepts `--<flag>=<bool>` and `--no-<flag>` (see `CommandConfig.ImportFlagSet`)
+				if flag.FlagValue != nil && isBoolValue(flag.FlagValue) {
+					negated, _ := isInvertedFlag(value)
+					negated = negated || flag.IsInverted
+					b := true
+					if len(valuesToProcess) > 0 && valueIndex(formattedCount-len(valuesToProcess)) == index {
+						var err error
+						if b, err = strconv.ParseBool(valuesToProcess[0]); err != nil {
+							return nil, ErrorUnsupportedValue{flag.Name, valuesToProcess[0]}
+						}
+						valuesToProcess = valuesToProcess[1:]
+					}
+					store.Flags[flag.Name] = flag.Store(strconv.FormatBool(b != negated))
+				}
+				store.addFlagOccurrence(flag, store.Flags[flag.Name].Value, index, values)
 			} else {
-				if nextValue, nextValuesToProcess := nextValue(valuesToProcess); len(nextValue) != 0 && !isFlag(nextValue) {
+				if nextValue, nextValuesToProcess, ok := nextValue(valuesToProcess); ok && !isFlag(nextValue) {
 					if !flag.Validate(nextValue) {
-						return nil, ErrorUnsupportedValue{flag.Name, nextValue}
+						return nil, ErrorUnsupportedValue{flag.Name, flag.redact(nextValue)}
 					}
 					store.Flags[flag.Name] = flag.Store(nextValue)
 					valuesToProcess = nextValuesToProcess
+					store.addFlagOccurrence(flag, nextValue, index, values)
 				}
 			}
 		} else {
 
-			// process as argument
-			for index, argName := range commandConfig.ArgNames {
+			if flagFound && ordering == OrderFlagsAfterArgs {
+				return nil, ErrorMisplacedArg{value}
+			}
 
-				// get argument object stored in the `commandConfig`
-				varg := commandConfig.Args[argName]
+			// process as argument
+			positionals = append(positionals, positionalValue{value, lastIndex(), false})
+		}
+	}
 
-				if !varg.Validate(value) {
-					return nil, ErrorUnsupportedValue{varg.Name, value}
-				}
+	// bind the values after the command name
+	if restStart != -1 {
+		bindValues(restStart, len(values))
+	}
 
-				arg, exist := store.Args[varg.Name]
-				if !exist {
-					arg = &Arg{
-						Name:       varg.Name,
-						IsVariadic: varg.IsVariadic,
-					}
-					store.Args[arg.Name] = arg
-				}
+	// bind the positional values to the arguments
+	if err := commandConfig.matchArgs(store, positionals, values); err != nil {
+		return nil, err
+	}
 
-				// assign value if value of the argument is empty
-				if len(arg.Value) == 0 {
-					arg.Value = value
-					break
-				}
+	// get the missing flag values from the environment and configurations
+	if err := registry.lookupValues(commandConfig, store); err != nil {
+		return nil, err
+	}
 
-				// if last argument is a variadic argument, append values
-				if (index == len(commandConfig.ArgNames)-1) && arg.IsVariadic {
-					arg.Value += "," + value
-				}
-			}
-		}
+	// check the required flags and arguments
+	if err := registry.requireValues(commandConfig, store); err != nil {
+		return nil, err
 	}
 
 	for k := range commandConfig.Flags {
-		if _, exist := store.Flags[k]; !exist {
+		if _, exist := store.Flags[k]; !exist && commandConfig.Flags[k].ReplacedBy == "" {
 			store.Flags[k] = commandConfig.Flags[k].StoreDefault()
 		}
 	}
@@ -381,13 +825,58 @@ func (registry Registry) Parse(values []string) (*CommandParsed, error) {
 			store.Args[k] = commandConfig.Args[k].StoreDefault()
 		}
 	}
+	if globalFlags {
+		for k, flag := range rootCommandConfig.Flags {
+			if _, exist := store.Flags[k]; !exist && flag.ReplacedBy == "" {
+				store.Flags[k] = flag.StoreDefault()
+			}
+		}
+	}
+
+	if registry.PrintWarnings {
+		o := registry.output(registry.stderr())
+		for _, warning := range store.Warnings {
+			fmt.Fprintln(registry.stderr(), o.paint(o.theme.Warning, registry.messages().format("warning", warning)))
+		}
+	}
+
+	if dumpArgs {
+		if err := store.Dump(registry.stdout()); err != nil {
+			return nil, err
+		}
+		return nil, ErrorDumpArgs{}
+	}
+
+	// write the version of the program for the `version` command
+	if commandConfig.printsVersion {
+		asJSON := false
+		if flag, ok := store.Flags["json"]; ok {
+			asJSON = flag.Value == "true"
+		}
+		if err := registry.writeVersion(registry.stdout(), commandConfig.Version, asJSON); err != nil {
+			return nil, err
+		}
+		return nil, ErrorVersion{}
+	}
+
+	// set the values of the bound flags and arguments
+	bindings := commandConfig.flagBindings(store)
+	if globalFlags {
+		bindings = append(bindings, commandConfig.globalFlags(rootCommandConfig).flagBindings(store)...)
+	}
+	bindings = append(bindings, commandConfig.argBindings(store)...)
+	if err := setBindings(bindings); err != nil {
+		return nil, err
+	}
 
 	return store, nil
 }
 
 // NewRegistry returns new instance of the "Registry"
-func NewRegistry() Registry {
-	return make(Registry)
+func NewRegistry() *Registry {
+	return &Registry{
+		Commands: make(map[string]*CommandConfig),
+	}
 }
 
 /*---------------------*/
@@ -398,6 +887,36 @@ type CommandConfig struct {
 	// name of the sub-command ("" for the root command)
 	Name string
 
+	// alternative names of the sub-command
+	Aliases []string
+
+	// description of the command
+	Description string
+
+	// deprecation message of the command (the command is deprecated if not empty)
+	Deprecated string
+
+	// name of the command replacing this deprecated command
+	ReplacedBy string
+
+	// if the command is omitted from the help and completion
+	Hidden bool
+
+	// placement of the flags and arguments (the registry ordering if `OrderDefault`)
+	Ordering Ordering
+
+	// if unregistered flags are collected in `CommandParsed.Unknown` (see `Registry.AllowUnknownFlags`)
+	AllowUnknownFlags bool
+
+	// if the arguments can be passed by name (see `CommandConfig.SetNamedArgs`)
+	NamedArgs bool
+
+	// version printed by the `--version` and `-V` flags (see `CommandConfig.SetVersion`)
+	Version *VersionInfo
+
+	// if the command prints the version (see `Registry.RegisterVersion`)
+	printsVersion bool
+
 	// command-line flags
 	Flags map[string]*FlagCommand
 
@@ -411,25 +930,61 @@ type CommandConfig struct {
 	ArgNames []string
 }
 
+// SetDescription sets the description of the command.
+func (commandConfig *CommandConfig) SetDescription(description string) *CommandConfig {
+	commandConfig.Description = description
+	return commandConfig
+}
+
+// FlagNames returns the names of the registered flags in sorted order.
+func (commandConfig *CommandConfig) FlagNames() []string {
+	names := make([]string, 0, len(commandConfig.Flags))
+	for name := range commandConfig.Flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
 // CommandParsed type holds the structure and values of the command-line arguments of command (final parsed version).
 type CommandParsed struct {
 	// name of the sub-command ("" for the root command)
 	Name string
 
+	// path of the executable of a plugin command (see `Registry.RunPlugin`)
+	Plugin string
+
 	// command-line flags
 	Flags map[string]*Flag
 
 	// registered command argument values
 	Args map[string]*Arg
+
+	// list of the argument names (for ordered iteration)
+	ArgNames []string
+
+	// flags and arguments in the order of the command-line arguments
+	Occurrences []*Occurrence
+
+	// values after the `--` marker which are not bound to the arguments
+	Passthrough []string
+
+	// unregistered flags with their values in the order of the command-line arguments (see `Registry.AllowUnknownFlags`)
+	Unknown []string
+
+	// warnings about the usage of deprecated commands and flags in the language of the registry (see `Registry.Catalog`)
+	Warnings []string
 }
 
 // AddArg registers an argument configuration with the command.
 // The `name` argument represents the name of the argument.
 // If value of the `name` argument ends with `...` suffix, then it is a variadic argument.
-// Variadic argument can accept multiple argument values and it should be the last registered argument.
+// Variadic argument can accept multiple argument values. It can be followed by other arguments,
+// it leaves the values needed by the required arguments after it (see `ArgCommand.SetNonGreedy`).
 // Values of a variadic argument will be concatenated using comma (,).
 // The `defaultValue` argument represents the default value of the argument.
-// All arguments without a default value must be registered first.
+// Optional arguments (see `ArgCommand.SetRequired`) take the values left by the required arguments in order.
 // If an argument with given `name` is already registered, then argument registration is skipped
 // and registered `*Arg` object returned.
 // If the argument is already registered, second return value will be `true`.
@@ -511,11 +1066,11 @@ func (commandConfig *CommandConfig) AddFlag(name string, shortName string, isBoo
 	if isBool {
 
 		// check for an inverted flag
-		if strings.HasPrefix(name, "no-") {
-			_isInvert = true                      // is an inverted flag
-			_name = strings.TrimLeft(name, "no-") // trim `no-` prefix
-			_defaultValue = "true"                // default value of an inverted flag is `true`
-			_shortName = ""                       // no short flag name for an inverted flag
+		if strings.HasPrefix(_name, "no-") {
+			_isInvert = true                         // is an inverted flag
+			_name = strings.TrimPrefix(_name, "no-") // trim `no-` prefix
+			_defaultValue = "true"                   // default value of an inverted flag is `true`
+			_shortName = ""                          // no short flag name for an inverted flag
 		} else {
 			_defaultValue = "false" // default value of a boolean flag is `true`
 		}
@@ -582,30 +1137,77 @@ type FlagCommand struct {
 	// if the flag is an inverted flag (with `--no-` prefix)
 	IsInverted bool
 
+	// description of the flag
+	Description string
+
+	// if the flag must be provided in the command-line arguments
+	Required bool
+
+	// if the flag holds a sensitive value (see `FlagCommand.SetSensitive`)
+	Sensitive bool
+
+	// name of the environment variable providing the value of the flag (see `FlagCommand.SetEnvVar`)
+	EnvVar string
+
+	// deprecation message of the flag (the flag is deprecated if not empty)
+	Deprecated string
+
+	// name of the flag replacing this deprecated flag
+	ReplacedBy string
+
+	// if the flag is omitted from the help and completion
+	Hidden bool
+
 	// default value of the flag
 	DefaultValue string
 
-	// value of the flag (provided by the user)
+	// unused, the parsed value is stored in `Flag.Value`
 	Value string
 
+	// value set with the parsed value of the flag (see `FlagCommand.SetFlagValue`)
+	FlagValue flag.Value
+
 	// ValidVals is list of all valid arg values that are accepted
 	ValidVals map[string]bool
 
+	// registration order of the valid values
+	validValsOrder []string
+
 	// ValidValsFunction is an optional function that provides valid arg values
 	// It is a dynamic version of using ValidArgs.
 	// Only one of ValidArgs and ValidArgsFunction can be used for a command.
 	// ValidValsFunction func(args []string, toComplete string) []string
 }
 
+// SetValidVals sets the list of values accepted by the flag (an empty list accepts any value).
 func (f *FlagCommand) SetValidVals(validVals []string) *FlagCommand {
-	if len(validVals) == 0 {
-		f.ValidVals = nil
-	} else {
-		f.ValidVals = make(map[string]bool)
-		for _, validate := range validVals {
-			f.ValidVals[validate] = true
-		}
-	}
+	f.ValidVals, f.validValsOrder = makeValidVals(validVals)
+	return f
+}
+
+// ValidValsList returns the values accepted by the flag in registration order.
+func (f *FlagCommand) ValidValsList() []string {
+	return orderedValidVals(f.ValidVals, f.validValsOrder)
+}
+
+// SetDescription sets the description of the flag.
+func (f *FlagCommand) SetDescription(description string) *FlagCommand {
+	f.Description = description
+	return f
+}
+
+// SetRequired sets if the flag must be provided in the command-line arguments.
+func (f *FlagCommand) SetRequired(required bool) *FlagCommand {
+	f.Required = required
+	return f
+}
+
+// SetSensitive sets if the flag holds a sensitive value.
+// A sensitive value is replaced with `RedactedValue` in the formatted `Flag`, JSON output, help and error messages,
+// and it is read without echo when prompted. The value of a sensitive `<flag>` can be also read from a file
+// with `--<flag>-file <path>` (or from the standard input with `--<flag>-file -`), so it is not visible in the process list.
+func (f *FlagCommand) SetSensitive(sensitive bool) *FlagCommand {
+	f.Sensitive = sensitive
 	return f
 }
 
@@ -619,20 +1221,26 @@ func (f *FlagCommand) Validate(v string) bool {
 	return true
 }
 
+// Store returns the parsed flag with the value provided in the command-line arguments.
 func (f *FlagCommand) Store(v string) *Flag {
+	return f.StoreFrom(v, SourceArgv, "")
+}
+
+// StoreFrom returns the parsed flag with the value from the source (see `Flag.SourceName` for the `sourceName`).
+func (f *FlagCommand) StoreFrom(v string, source ValueSource, sourceName string) *Flag {
 	return &Flag{
-		Name:      f.Name,
-		IsBoolean: f.IsBoolean,
-		Value:     v,
+		Name:       f.Name,
+		IsBoolean:  f.IsBoolean,
+		Sensitive:  f.Sensitive,
+		Value:      v,
+		Source:     source,
+		SourceName: sourceName,
 	}
 }
 
+// StoreDefault returns the parsed flag with the default value.
 func (f *FlagCommand) StoreDefault() *Flag {
-	return &Flag{
-		Name:      f.Name,
-		IsBoolean: f.IsBoolean,
-		Value:     f.DefaultValue,
-	}
+	return f.StoreFrom(f.DefaultValue, SourceDefault, "")
 }
 
 // Flag type holds the structured information about a flag.
@@ -643,8 +1251,30 @@ type Flag struct {
 	// if the flag holds boolean value
 	IsBoolean bool
 
+	// if the flag holds a sensitive value (redacted when formatted)
+	Sensitive bool
+
 	// value of the flag (provided by the user)
 	Value string
+
+	// source of the value
+	Source ValueSource
+
+	// location of the value in the source: the environment variable name (`SourceEnv`),
+	// the configuration and the key `<config>:<key>` (`SourceConfig`) or the file path (`SourceFile`)
+	SourceName string
+}
+
+// IsSet method returns true if the value of the flag is not the default value (it is provided by any source).
+func (flag *Flag) IsSet() bool {
+	return flag.Source != SourceDefault
+}
+
+// Changed method returns true if the value of the flag is explicitly provided by the user in this invocation
+// (in the command-line arguments, a file named in the arguments or at the prompt),
+// unlike the environment, configuration and default values.
+func (flag *Flag) Changed() bool {
+	return flag.Source == SourceArgv || flag.Source == SourceFile || flag.Source == SourcePrompt
 }
 
 /*---------------------*/
@@ -657,30 +1287,56 @@ type ArgCommand struct {
 	// variadic argument can take multiple values
 	IsVariadic bool
 
+	// if a variadic argument takes only the values not matched by the following arguments (see `ArgCommand.SetNonGreedy`)
+	NonGreedy bool
+
+	// description of the argument
+	Description string
+
+	// if the argument must be provided in the command-line arguments
+	Required bool
+
 	// default value of the argument
 	DefaultValue string
 
-	// value of the argument (provided by the user)
+	// unused, the parsed value is stored in `Arg.Value`
 	Value string
 
 	// ValidVals is list of all valid arg values that are accepted
 	ValidVals map[string]bool
 
+	// registration order of the valid values
+	validValsOrder []string
+
+	// if not nil, the parsed values are set with `flag.Value.Set` (see `ArgCommand.SetArgValue`)
+	ArgValue flag.Value
+
 	// ValidValsFunction is an optional function that provides valid arg values
 	// It is a dynamic version of using ValidArgs.
 	// Only one of ValidArgs and ValidArgsFunction can be used for a command.
 	// ValidValsFunction func(args []string, toComplete string) []string
 }
 
+// SetValidVals sets the list of values accepted by the argument (an empty list accepts any value).
 func (a *ArgCommand) SetValidVals(validVals []string) *ArgCommand {
-	if len(validVals) == 0 {
-		a.ValidVals = nil
-	} else {
-		a.ValidVals = make(map[string]bool)
-		for _, validate := range validVals {
-			a.ValidVals[validate] = true
-		}
-	}
+	a.ValidVals, a.validValsOrder = makeValidVals(validVals)
+	return a
+}
+
+// ValidValsList returns the values accepted by the argument in registration order.
+func (a *ArgCommand) ValidValsList() []string {
+	return orderedValidVals(a.ValidVals, a.validValsOrder)
+}
+
+// SetDescription sets the description of the argument.
+func (a *ArgCommand) SetDescription(description string) *ArgCommand {
+	a.Description = description
+	return a
+}
+
+// SetRequired sets if the argument must be provided in the command-line arguments.
+func (a *ArgCommand) SetRequired(required bool) *ArgCommand {
+	a.Required = required
 	return a
 }
 
@@ -694,21 +1350,24 @@ func (a *ArgCommand) Validate(v string) bool {
 	return true
 }
 
+// Store returns the parsed argument with the value provided in the command-line arguments.
 func (a *ArgCommand) Store(v string) *Arg {
+	return a.StoreFrom(v, SourceArgv)
+}
+
+// StoreFrom returns the parsed argument with the value from the source.
+func (a *ArgCommand) StoreFrom(v string, source ValueSource) *Arg {
 	return &Arg{
 		Name:       a.Name,
 		IsVariadic: a.IsVariadic,
 		Value:      v,
+		Source:     source,
 	}
-
 }
 
+// StoreDefault returns the parsed argument with the default value.
 func (a *ArgCommand) StoreDefault() *Arg {
-	return &Arg{
-		Name:       a.Name,
-		IsVariadic: a.IsVariadic,
-		Value:      a.DefaultValue,
-	}
+	return a.StoreFrom(a.DefaultValue, SourceDefault)
 }
 
 // Arg type holds the structured information about an argument.
@@ -721,4 +1380,17 @@ type Arg struct {
 
 	// value of the argument (provided by the user)
 	Value string
+
+	// source of the value
+	Source ValueSource
+}
+
+// IsSet method returns true if the value of the argument is not the default value.
+func (arg *Arg) IsSet() bool {
+	return arg.Source != SourceDefault
+}
+
+// Changed method returns true if the value of the argument is explicitly provided by the user in this invocation.
+func (arg *Arg) Changed() bool {
+	return arg.Source == SourceArgv || arg.Source == SourcePrompt
 }
@@ -1,13 +1,47 @@
-package clapper
+package clapper_test
 
 import (
-	"fmt"
-	"os"
-	"os/exec"
-	"strings"
 	"testing"
+
+	"github.com/msaf1980/clapper"
+	"github.com/msaf1980/clapper/clappertest"
 )
 
+// registry of `demo/cmd.go`
+func newDemoRegistry(withRoot bool) *clapper.Registry {
+
+	// create a new registry
+	registry := clapper.NewRegistry()
+	registry.Name = "demo"
+
+	// register the root command
+	if withRoot {
+		rootCommand, _ := registry.Register("")             // root command
+		rootCommand.AddArg("output", "")                    //
+		rootCommand.AddFlag("force", "f", true, "")         // --force, -f | default value: "false"
+		rootCommand.AddFlag("verbose", "v", true, "")       // --verbose, -v | default value: "false"
+		rootCommand.AddFlag("version", "V", false, "")      // --version, -V <value>
+		rootCommand.AddFlag("dir", "", false, "/var/users") // --dir <value> | default value: "/var/users"
+	}
+
+	// register the `info` sub-command
+	infoCommand, _ := registry.Register("info")        // sub-command
+	infoCommand.AddArgWithValid("category", "manager", // default value: manager
+		[]string{"manager", "student", "thatisuday", "math", "science", "physics"})
+	infoCommand.AddArg("username", "")                           //
+	infoCommand.AddArg("subjects...", "")                        // variadic argument
+	infoCommand.AddFlag("verbose", "v", true, "")                // --verbose, -v | default value: "false"
+	infoCommand.AddFlagWithValid("version", "V", false, "1.0.1", // --version, -V <value> | default value: "1.0.1"
+		[]string{"", "1.0.1", "2.0.0"})
+	infoCommand.AddFlag("output", "o", false, "./") // --output, -o <value> | default value: "./"
+	infoCommand.AddFlag("no-clean", "", true, "")   // --no-clean | default value: "true"
+
+	// register the `ghost` sub-command
+	registry.Register("ghost")
+
+	return registry
+}
+
 /*----------------*/
 
 // test unsupported flag
@@ -15,72 +49,40 @@ func TestUnsupportedAssignment(t *testing.T) {
 
 	// options
 	options := map[string][]string{
-		"---version": []string{"---version"},
-		"---v":       []string{"---v=1.0.0"},
-		"-version":   []string{"-version"},
+		"---version": {"---version"},
+		"---v":       {"---v=1.0.0"},
+		"-version":   {"-version"},
 	}
 
 	for flag, options := range options {
-		// command
-		cmd := exec.Command("go", append([]string{"run", "demo/cmd.go"}, options...)...)
-
-		// get output
-		if output, err := cmd.Output(); err != nil {
-			t.Fatalf("Error: %v, out: %q", err, string(output))
-		} else {
-			if !strings.Contains(fmt.Sprintf("%s", output), fmt.Sprintf(`error => clapper.ErrorUnsupportedFlag{Name:"%s"}`, flag)) {
-				t.Fail()
-			}
-		}
+		result := clappertest.Run(t, newDemoRegistry(true), clappertest.Fixture{Args: options})
+		clappertest.AssertError(t, result.Err, clapper.ErrorUnsupportedFlag{Name: flag})
 	}
 }
 
 // test empty root command
 func TestEmptyRootCommand(t *testing.T) {
-	// command
-	cmd := exec.Command("go", "run", "demo/cmd.go")
-
-	// get output
-	if output, err := cmd.Output(); err != nil {
-		t.Fatalf("Error: %v, out: %q", err, string(output))
-	} else {
-		lines := []string{
-			`sub-command => ""`,
-			`argument(output) => &clapper.Arg{Name:"output", IsVariadic:false, Value:""}`,
-			`flag(force) => &clapper.Flag{Name:"force", IsBoolean:true, Value:"false"}`,
-			`flag(verbose) => &clapper.Flag{Name:"verbose", IsBoolean:true, Value:"false"}`,
-			`flag(version) => &clapper.Flag{Name:"version", IsBoolean:false, Value:""}`,
-			`flag(dir) => &clapper.Flag{Name:"dir", IsBoolean:false, Value:"/var/users"}`,
-		}
-
-		for _, line := range lines {
-			if !strings.Contains(fmt.Sprintf("%s", output), line) {
-				t.Fatalf("got\n%q\nwant line\n%q", output, line)
-			}
-		}
+	command := clappertest.Parse(t, newDemoRegistry(true))
+
+	clappertest.AssertCommand(t, command, "")
+	clappertest.AssertArgs(t, command, map[string]string{
+		"output": "",
+	})
+	clappertest.AssertFlags(t, command, map[string]string{
+		"force":   "false",
+		"verbose": "false",
+		"version": "",
+		"dir":     "/var/users",
+	})
+	if !command.Flags["force"].IsBoolean || command.Flags["version"].IsBoolean || command.Args["output"].IsVariadic {
+		t.Errorf("got %#v", command)
 	}
 }
 
 // test root command when not registered
 func TestUnregisteredRootCommand(t *testing.T) {
-	// command
-	cmd := exec.Command("go", "run", "demo/cmd.go")
-	cmd.Env = append(os.Environ(), "NO_ROOT=TRUE")
-
-	// get output
-	if output, err := cmd.Output(); err != nil {
-		t.Fatalf("Error: %v, out: %q", err, string(output))
-	} else {
-		lines := []string{
-			`error => clapper.ErrorUnknownCommand{Name:""}`,
-		}
-
-		for _, line := range lines {
-			if !strings.Contains(fmt.Sprintf("%s", output), line) {
-				t.Fatalf("got\n%q\nwant line\n%q", output, line)
-			}
-		}
-	}
+	result := clappertest.Run(t, newDemoRegistry(false), clappertest.Fixture{})
+	clappertest.AssertError(t, result.Err, clapper.ErrorUnknownCommand{Name: ""})
 }
 
 // test an unregistered flag
@@ -88,25 +90,15 @@ func TestUnregisteredFlag(t *testing.T) {
 
 	// flags
 	flags := map[string][]string{
-		"-d":          []string{"-V", "1.0.1", "-v", "--force", "-d", "./sub/dir"},
-		"--m":         []string{"-V", "1.0.1", "-v", "--force", "--m", "./sub/dir"},
-		"--directory": []string{"-V", "1.0.1", "-v", "--force", "--directory", "./sub/dir"},
+		"-d":          {"-V", "1.0.1", "-v", "--force", "-d", "./sub/dir"},
+		"--m":         {"-V", "1.0.1", "-v", "--force", "--m", "./sub/dir"},
+		"--directory": {"-V", "1.0.1", "-v", "--force", "--directory", "./sub/dir"},
 	}
 
 	for flag, options := range flags {
-		// command
-		cmd := exec.Command("go", append([]string{"run", "demo/cmd.go"}, options...)...)
-
-		// get output
-		if output, err := cmd.Output(); err != nil {
-			t.Fatalf("Error: %v, out: %q", err, string(output))
-		} else {
-			if !strings.Contains(fmt.Sprintf("%s", output), fmt.Sprintf(`error => clapper.ErrorUnknownFlag{Name:"%s"}`, flag)) {
-				t.Fail()
-			}
-		}
+		result := clappertest.Run(t, newDemoRegistry(true), clappertest.Fixture{Args: options})
+		clappertest.AssertError(t, result.Err, clapper.ErrorUnknownFlag{Name: flag})
 	}
-
 }
 
 // test for valid inverted flag values
@@ -114,35 +106,25 @@ func TestValidInvertFlagValues(t *testing.T) {
 
 	// options list
 	optionsList := [][]string{
-		[]string{"info", "student", "-V", "-v", "--output", "./opt/dir", "--no-clean"},
-		[]string{"info", "student", "--version", "--no-clean", "--output", "./opt/dir", "--verbose"},
+		{"info", "student", "-V", "-v", "--output", "./opt/dir", "--no-clean"},
+		{"info", "student", "--version", "--no-clean", "--output", "./opt/dir", "--verbose"},
 	}
 
 	for _, options := range optionsList {
-		// command
-		cmd := exec.Command("go", append([]string{"run", "demo/cmd.go"}, options...)...)
-
-		// get output
-		if output, err := cmd.Output(); err != nil {
-			t.Fatalf("Error: %v, out: %q", err, string(output))
-		} else {
-			lines := []string{
-				`sub-command => "info"`,
-				`argument(category) => &clapper.Arg{Name:"category", IsVariadic:false, Value:"student"}`,
-				`argument(username) => &clapper.Arg{Name:"username", IsVariadic:false, Value:""}`,
-				`argument(subjects) => &clapper.Arg{Name:"subjects", IsVariadic:true, Value:""}`,
-				`flag(version) => &clapper.Flag{Name:"version", IsBoolean:false, Value:"1.0.1"}`,
-				`flag(output) => &clapper.Flag{Name:"output", IsBoolean:false, Value:"./opt/dir"}`,
-				`flag(verbose) => &clapper.Flag{Name:"verbose", IsBoolean:true, Value:"true"}`,
-				`flag(clean) => &clapper.Flag{Name:"clean", IsBoolean:true, Value:"false"}`,
-			}
-
-			for _, line := range lines {
-				if !strings.Contains(fmt.Sprintf("%s", output), line) {
-					t.Fatalf("got\n%q\nwant line\n%q", output, line)
-				}
-			}
-		}
+		command := clappertest.Parse(t, newDemoRegistry(true), options...)
+
+		clappertest.AssertCommand(t, command, "info")
+		clappertest.AssertArgs(t, command, map[string]string{
+			"category": "student",
+			"username": "",
+			"subjects": "",
+		})
+		clappertest.AssertFlags(t, command, map[string]string{
+			"version": "1.0.1",
+			"output":  "./opt/dir",
+			"verbose": "true",
+			"clean":   "false",
+		})
 	}
 }
 
@@ -151,22 +133,13 @@ func TestErrorUnknownFlagForInvertFlags(t *testing.T) {
 
 	// options list
 	optionsList := map[string][]string{
-		"--clean":   []string{"info", "student", "-V", "-v", "--output", "./opt/dir", "--clean"},
-		"--no-dump": []string{"info", "student", "--version", "--no-dump", "--output", "./opt/dir", "--verbose"},
+		"--clean":   {"info", "student", "-V", "-v", "--output", "./opt/dir", "--clean"},
+		"--no-dump": {"info", "student", "--version", "--no-dump", "--output", "./opt/dir", "--verbose"},
 	}
 
 	for flag, options := range optionsList {
-		// command
-		cmd := exec.Command("go", append([]string{"run", "demo/cmd.go"}, options...)...)
-
-		// get output
-		if output, err := cmd.Output(); err != nil {
-			t.Fatalf("Error: %v, out: %q", err, string(output))
-		} else {
-			if !strings.Contains(fmt.Sprintf("%s", output), fmt.Sprintf(`error => clapper.ErrorUnknownFlag{Name:"%s"}`, flag)) {
-				t.Fail()
-			}
-		}
+		result := clappertest.Run(t, newDemoRegistry(true), clappertest.Fixture{Args: options})
+		clappertest.AssertError(t, result.Err, clapper.ErrorUnknownFlag{Name: flag})
 	}
 }
 
@@ -175,34 +148,24 @@ func TestFlagAssignmentSyntax(t *testing.T) {
 
 	// options list
 	optionsList := [][]string{
-		[]string{"info", "student", "-v", "--version=2.0.0", "thatisuday"},
-		[]string{"info", "student", "thatisuday", "-v", "-V=2.0.0"},
+		{"info", "student", "-v", "--version=2.0.0", "thatisuday"},
+		{"info", "student", "thatisuday", "-v", "-V=2.0.0"},
 	}
 
 	for _, options := range optionsList {
-		// command
-		cmd := exec.Command("go", append([]string{"run", "demo/cmd.go"}, options...)...)
-
-		// get output
-		if output, err := cmd.Output(); err != nil {
-			t.Fatalf("Error: %v, out: %q", err, string(output))
-		} else {
-			lines := []string{
-				`sub-command => "info"`,
-				`argument(category) => &clapper.Arg{Name:"category", IsVariadic:false, Value:"student"}`,
-				`argument(username) => &clapper.Arg{Name:"username", IsVariadic:false, Value:"thatisuday"}`,
-				`argument(subjects) => &clapper.Arg{Name:"subjects", IsVariadic:true, Value:""}`,
-				`flag(version) => &clapper.Flag{Name:"version", IsBoolean:false, Value:"2.0.0"}`,
-				`flag(output) => &clapper.Flag{Name:"output", IsBoolean:false, Value:"./"}`,
-				`flag(verbose) => &clapper.Flag{Name:"verbose", IsBoolean:true, Value:"true"}`,
-			}
-
-			for _, line := range lines {
-				if !strings.Contains(fmt.Sprintf("%s", output), line) {
-					t.Fatalf("got\n%q\nwant line\n%q", output, line)
-				}
-			}
-		}
+		command := clappertest.Parse(t, newDemoRegistry(true), options...)
+
+		clappertest.AssertCommand(t, command, "info")
+		clappertest.AssertArgs(t, command, map[string]string{
+			"category": "student",
+			"username": "thatisuday",
+			"subjects": "",
+		})
+		clappertest.AssertFlags(t, command, map[string]string{
+			"version": "2.0.0",
+			"output":  "./",
+			"verbose": "true",
+		})
 	}
 }
 
@@ -211,34 +174,27 @@ func TestValidVariadicArgumentValues(t *testing.T) {
 
 	// options list
 	optionsList := [][]string{
-		[]string{"info", "student", "thatisuday", "-V", "-v", "--output", "./opt/dir", "--no-clean", "math", "science", "physics"},
-		[]string{"info", "student", "--version", "--no-clean", "thatisuday", "--output", "./opt/dir", "math", "science", "--verbose", "physics"},
+		{"info", "student", "thatisuday", "-V", "-v", "--output", "./opt/dir", "--no-clean", "math", "science", "physics"},
+		{"info", "student", "--version", "--no-clean", "thatisuday", "--output", "./opt/dir", "math", "science", "--verbose", "physics"},
 	}
 
 	for _, options := range optionsList {
-		// command
-		cmd := exec.Command("go", append([]string{"run", "demo/cmd.go"}, options...)...)
-
-		// get output
-		if output, err := cmd.Output(); err != nil {
-			t.Fatalf("Error: %v, out: %q", err, string(output))
-		} else {
-			lines := []string{
-				`sub-command => "info"`,
-				`argument(category) => &clapper.Arg{Name:"category", IsVariadic:false, Value:"student"}`,
-				`argument(username) => &clapper.Arg{Name:"username", IsVariadic:false, Value:"thatisuday"}`,
-				`argument(subjects) => &clapper.Arg{Name:"subjects", IsVariadic:true, Value:"math,science,physics"}`,
-				`flag(version) => &clapper.Flag{Name:"version", IsBoolean:false, Value:"1.0.1"}`,
-				`flag(output) => &clapper.Flag{Name:"output", IsBoolean:false, Value:"./opt/dir"}`,
-				`flag(verbose) => &clapper.Flag{Name:"verbose", IsBoolean:true, Value:"true"}`,
-				`flag(clean) => &clapper.Flag{Name:"clean", IsBoolean:true, Value:"false"}`,
-			}
-
-			for _, line := range lines {
-				if !strings.Contains(fmt.Sprintf("%s", output), line) {
-					t.Fatalf("got\n%q\nwant line\n%q", output, line)
-				}
-			}
+		command := clappertest.Parse(t, newDemoRegistry(true), options...)
+
+		clappertest.AssertCommand(t, command, "info")
+		clappertest.AssertArgs(t, command, map[string]string{
+			"category": "student",
+			"username": "thatisuday",
+			"subjects": "math,science,physics",
+		})
+		clappertest.AssertFlags(t, command, map[string]string{
+			"version": "1.0.1",
+			"output":  "./opt/dir",
+			"verbose": "true",
+			"clean":   "false",
+		})
+		if !command.Args["subjects"].IsVariadic {
+			t.Errorf("got %#v", command.Args["subjects"])
 		}
 	}
 }
@@ -250,35 +206,25 @@ func TestRootCommandWithOptions(t *testing.T) {
 
 	// options list
 	optionsList := [][]string{
-		[]string{"userinfo", "-V", "1.0.1", "-v", "--force", "--dir", "./sub/dir"},
-		[]string{"-V", "1.0.1", "--verbose", "--force", "userinfo", "--dir", "./sub/dir"},
-		[]string{"-V", "1.0.1", "-v", "--force", "--dir", "./sub/dir", "userinfo"},
-		[]string{"--version", "1.0.1", "--verbose", "--force", "--dir", "./sub/dir", "userinfo"},
+		{"userinfo", "-V", "1.0.1", "-v", "--force", "--dir", "./sub/dir"},
+		{"-V", "1.0.1", "--verbose", "--force", "userinfo", "--dir", "./sub/dir"},
+		{"-V", "1.0.1", "-v", "--force", "--dir", "./sub/dir", "userinfo"},
+		{"--version", "1.0.1", "--verbose", "--force", "--dir", "./sub/dir", "userinfo"},
 	}
 
 	for _, options := range optionsList {
-		// command
-		cmd := exec.Command("go", append([]string{"run", "demo/cmd.go"}, options...)...)
-
-		// get output
-		if output, err := cmd.Output(); err != nil {
-			t.Fatalf("Error: %v, out: %q", err, string(output))
-		} else {
-			lines := []string{
-				`sub-command => ""`,
-				`argument(output) => &clapper.Arg{Name:"output", IsVariadic:false, Value:"userinfo"}`,
-				`flag(force) => &clapper.Flag{Name:"force", IsBoolean:true, Value:"true"}`,
-				`flag(verbose) => &clapper.Flag{Name:"verbose", IsBoolean:true, Value:"true"}`,
-				`flag(version) => &clapper.Flag{Name:"version", IsBoolean:false, Value:"1.0.1"}`,
-				`flag(dir) => &clapper.Flag{Name:"dir", IsBoolean:false, Value:"./sub/dir"}`,
-			}
-
-			for _, line := range lines {
-				if !strings.Contains(fmt.Sprintf("%s", output), line) {
-					t.Fatalf("got\n%q\nwant line\n%q", output, line)
-				}
-			}
-		}
+		command := clappertest.Parse(t, newDemoRegistry(true), options...)
+
+		clappertest.AssertCommand(t, command, "")
+		clappertest.AssertArgs(t, command, map[string]string{
+			"output": "userinfo",
+		})
+		clappertest.AssertFlags(t, command, map[string]string{
+			"force":   "true",
+			"verbose": "true",
+			"version": "1.0.1",
+			"dir":     "./sub/dir",
+		})
 	}
 }
 
@@ -287,35 +233,25 @@ func TestSubCommandWithOptions(t *testing.T) {
 
 	// options list
 	optionsList := [][]string{
-		[]string{"info", "student", "-V", "-v", "--output", "./opt/dir"},
-		[]string{"info", "student", "--version", "--output", "./opt/dir", "--verbose"},
+		{"info", "student", "-V", "-v", "--output", "./opt/dir"},
+		{"info", "student", "--version", "--output", "./opt/dir", "--verbose"},
 	}
 
 	for _, options := range optionsList {
-		// command
-		cmd := exec.Command("go", append([]string{"run", "demo/cmd.go"}, options...)...)
-
-		// get output
-		if output, err := cmd.Output(); err != nil {
-			t.Fatalf("Error: %v, out: %q", err, string(output))
-		} else {
-			lines := []string{
-				`sub-command => "info"`,
-				`argument(category) => &clapper.Arg{Name:"category", IsVariadic:false, Value:"student"}`,
-				`argument(username) => &clapper.Arg{Name:"username", IsVariadic:false, Value:""}`,
-				`argument(subjects) => &clapper.Arg{Name:"subjects", IsVariadic:true, Value:""}`,
-				`flag(version) => &clapper.Flag{Name:"version", IsBoolean:false, Value:"1.0.1"}`,
-				`flag(output) => &clapper.Flag{Name:"output", IsBoolean:false, Value:"./opt/dir"}`,
-				`flag(verbose) => &clapper.Flag{Name:"verbose", IsBoolean:true, Value:"true"}`,
-				`flag(clean) => &clapper.Flag{Name:"clean", IsBoolean:true, Value:"true"}`,
-			}
-
-			for _, line := range lines {
-				if !strings.Contains(fmt.Sprintf("%s", output), line) {
-					t.Fatalf("got\n%q\nwant line\n%q", output, line)
-				}
-			}
-		}
+		command := clappertest.Parse(t, newDemoRegistry(true), options...)
+
+		clappertest.AssertCommand(t, command, "info")
+		clappertest.AssertArgs(t, command, map[string]string{
+			"category": "student",
+			"username": "",
+			"subjects": "",
+		})
+		clappertest.AssertFlags(t, command, map[string]string{
+			"version": "1.0.1",
+			"output":  "./opt/dir",
+			"verbose": "true",
+			"clean":   "true",
+		})
 	}
 }
 
@@ -324,73 +260,78 @@ func TestSubCommandWithArguments(t *testing.T) {
 
 	// options list
 	optionsList := [][]string{
-		[]string{"info", "-v", "student", "-V", "2.0.0", "thatisuday"},
-		[]string{"info", "student", "-v", "thatisuday", "--version", "2.0.0"},
+		{"info", "-v", "student", "-V", "2.0.0", "thatisuday"},
+		{"info", "student", "-v", "thatisuday", "--version", "2.0.0"},
 	}
 
 	for _, options := range optionsList {
-		// command
-		cmd := exec.Command("go", append([]string{"run", "demo/cmd.go"}, options...)...)
-
-		// get output
-		if output, err := cmd.Output(); err != nil {
-			t.Fatalf("Error: %v, out: %q", err, string(output))
-		} else {
-			lines := []string{
-				`sub-command => "info"`,
-				`argument(category) => &clapper.Arg{Name:"category", IsVariadic:false, Value:"student"}`,
-				`argument(username) => &clapper.Arg{Name:"username", IsVariadic:false, Value:"thatisuday"}`,
-				`argument(subjects) => &clapper.Arg{Name:"subjects", IsVariadic:true, Value:""}`,
-				`flag(version) => &clapper.Flag{Name:"version", IsBoolean:false, Value:"2.0.0"}`,
-				`flag(output) => &clapper.Flag{Name:"output", IsBoolean:false, Value:"./"}`,
-				`flag(verbose) => &clapper.Flag{Name:"verbose", IsBoolean:true, Value:"true"}`,
-			}
-
-			for _, line := range lines {
-				if !strings.Contains(fmt.Sprintf("%s", output), line) {
-					t.Fatalf("got\n%q\nwant line\n%q", output, line)
-				}
-			}
-		}
+		command := clappertest.Parse(t, newDemoRegistry(true), options...)
+
+		clappertest.AssertCommand(t, command, "info")
+		clappertest.AssertArgs(t, command, map[string]string{
+			"category": "student",
+			"username": "thatisuday",
+			"subjects": "",
+		})
+		clappertest.AssertFlags(t, command, map[string]string{
+			"version": "2.0.0",
+			"output":  "./",
+			"verbose": "true",
+		})
 	}
 }
 
 // test validate arg
 func TestInvalidArg(t *testing.T) {
-	// options
-	options := []string{"info", "worker", "-V", "-v", "2.0.0"}
-
-	// command
-	cmd := exec.Command("go", append([]string{"run", "demo/cmd.go"}, options...)...)
-
-	// get output
-	if output, err := cmd.Output(); err != nil {
-		t.Fatalf("Error: %v, out: %q", err, string(output))
-	} else {
-		out := string(output)
-		want := "error => clapper.ErrorUnsupportedValue{Name:\"category\", Value:\"worker\"}\n"
-		if out != want {
-			t.Fatalf("got\n%q\nwant\n%q", out, want)
-		}
+	result := clappertest.Run(t, newDemoRegistry(true), clappertest.Fixture{
+		Args: []string{"info", "worker", "-V", "-v", "2.0.0"},
+	})
+	clappertest.AssertError(t, result.Err, clapper.ErrorUnsupportedValue{Name: "category", Value: "worker"})
+}
+
+// test validate each arg value only by its own arg
+func TestArgValidation(t *testing.T) {
+	registry := clapper.NewRegistry()
+	infoCommand, _ := registry.Register("info")
+	infoCommand.AddArgWithValid("category", "manager", []string{"manager", "student"})
+	infoCommand.AddArg("username", "")
+	infoCommand.AddArgWithValid("subjects...", "", []string{"math", "science"})
+
+	command := clappertest.Parse(t, registry, "info", "student", "thatisuday", "math", "science")
+	clappertest.AssertArgs(t, command, map[string]string{"category": "student", "username": "thatisuday", "subjects": "math,science"})
+
+	command = clappertest.Parse(t, registry, "info", "student", "")
+	clappertest.AssertArgs(t, command, map[string]string{"category": "student", "username": "", "subjects": ""})
+
+	for _, tt := range []struct {
+		args []string
+		err  error
+	}{
+		{[]string{"info", "thatisuday"}, clapper.ErrorUnsupportedValue{Name: "category", Value: "thatisuday"}},
+		{[]string{"info", "student", "thatisuday", "math", "art"}, clapper.ErrorUnsupportedValue{Name: "subjects", Value: "art"}},
+	} {
+		result := clappertest.Run(t, registry, clappertest.Fixture{Args: tt.args})
+		clappertest.AssertError(t, result.Err, tt.err)
 	}
 }
 
 // test validate flag
 func TestInvalidFlag(t *testing.T) {
-	// options
-	options := []string{"info", "student", "-V", "2.0.1", "-v"}
-
-	// command
-	cmd := exec.Command("go", append([]string{"run", "demo/cmd.go"}, options...)...)
-
-	// get output
-	if output, err := cmd.Output(); err != nil {
-		t.Fatalf("Error: %v, out: %q", err, string(output))
-	} else {
-		out := string(output)
-		want := "error => clapper.ErrorUnsupportedValue{Name:\"version\", Value:\"2.0.1\"}\n"
-		if out != want {
-			t.Fatalf("got\n%q\nwant\n%q", out, want)
-		}
+	result := clappertest.Run(t, newDemoRegistry(true), clappertest.Fixture{
+		Args: []string{"info", "student", "-V", "2.0.1", "-v"},
+	})
+	clappertest.AssertError(t, result.Err, clapper.ErrorUnsupportedValue{Name: "version", Value: "2.0.1"})
+}
+
+// test help text
+func TestHelp(t *testing.T) {
+	registry := newDemoRegistry(true)
+	registry.Commands["info"].SetDescription("print user information")
+
+	clappertest.Golden(t, "help-root", clappertest.Help(t, registry, ""))
+	clappertest.Golden(t, "help-info", clappertest.Help(t, registry, "info"))
+
+	if _, err := registry.Help("unknown"); err != (clapper.ErrorUnknownCommand{Name: "unknown"}) {
+		t.Errorf("got %#v", err)
 	}
 }
@@ -0,0 +1,265 @@
+// Package clappertest provides utilities for testing command-line interfaces built with clapper
+// in-process: running a registry against argument, environment and file fixtures,
+// asserting on parsed values and errors and comparing help text with golden files.
+package clappertest
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/msaf1980/clapper"
+)
+
+// DirPlaceholder is replaced with the path of the fixture directory in the fixture arguments and environment values.
+const DirPlaceholder = "$FIXTURE_DIR"
+
+// update golden files instead of comparing them
+var update = flag.Bool("clappertest.update", false, "update golden files")
+
+/*---------------------*/
+
+// Fixture type holds the input of a registry run.
+type Fixture struct {
+	// command-line arguments (without the program name)
+	Args []string
+
+	// environment variables looked up by the registry before the process environment (see `clapper.Registry.LookupEnv`),
+	// the process environment is not changed
+	Env map[string]string
+
+	// files created in a temporary fixture directory (relative path => content)
+	Files map[string]string
+
+	// configuration values (see `clapper.ConfigMap`) looked up before the registry configurations
+	Config map[string]string
+}
+
+// ConfigName is the name of the fixture configuration (see `clapper.Flag.SourceName`).
+const ConfigName = "fixture"
+
+// Result type holds the output of a registry run.
+type Result struct {
+	// parsed command (nil on error)
+	Command *clapper.CommandParsed
+
+	// parse error
+	Err error
+
+	// output written to `Registry.Stdout`
+	Stdout string
+
+	// output written to `Registry.Stderr`
+	Stderr string
+}
+
+// create fixture files in a temporary directory
+func writeFiles(t testing.TB, files map[string]string) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "clappertest")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			os.RemoveAll(dir)
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			os.RemoveAll(dir)
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+// Run parses the fixture arguments with a copy of the registry and captures the registry output.
+// The fixture files, environment variables and configuration are available only during the run,
+// the options of the registry are not changed (the commands are shared with the copy).
+func Run(t testing.TB, registry *clapper.Registry, fixture Fixture) *Result {
+	t.Helper()
+
+	dir := ""
+	if len(fixture.Files) > 0 {
+		dir = writeFiles(t, fixture.Files)
+		defer os.RemoveAll(dir)
+	}
+
+	expand := func(value string) string {
+		if dir == "" {
+			return value
+		}
+		return strings.Replace(value, DirPlaceholder, dir, -1)
+	}
+
+	args := make([]string, len(fixture.Args))
+	for i, arg := range fixture.Args {
+		args[i] = expand(arg)
+	}
+
+	// the run options are set on a copy of the registry
+	run := *registry
+
+	// look up the fixture environment first
+	if len(fixture.Env) > 0 {
+		env := make(map[string]string, len(fixture.Env))
+		for name, value := range fixture.Env {
+			env[name] = expand(value)
+		}
+		lookupEnv := registry.LookupEnv
+		if lookupEnv == nil {
+			lookupEnv = os.LookupEnv
+		}
+		run.LookupEnv = func(name string) (string, bool) {
+			if value, ok := env[name]; ok {
+				return value, true
+			}
+			return lookupEnv(name)
+		}
+	}
+
+	// capture the registry output
+	var stdout, stderr bytes.Buffer
+	run.Stdout, run.Stderr = &stdout, &stderr
+
+	// prepend the fixture configuration
+	if fixture.Config != nil {
+		config := &clapper.ConfigMap{Name: ConfigName, Values: make(map[string]string, len(fixture.Config))}
+		for key, value := range fixture.Config {
+			config.Values[key] = expand(value)
+		}
+		run.Configs = append([]clapper.ConfigSource{config}, registry.Configs...)
+	}
+
+	command, err := run.Parse(args)
+
+	return &Result{
+		Command: command,
+		Err:     err,
+		Stdout:  stdout.String(),
+		Stderr:  stderr.String(),
+	}
+}
+
+// Parse runs the registry with the arguments and fails the test on a parse error.
+func Parse(t testing.TB, registry *clapper.Registry, args ...string) *clapper.CommandParsed {
+	t.Helper()
+
+	result := Run(t, registry, Fixture{Args: args})
+	if result.Err != nil {
+		t.Fatalf("%q: unexpected error: %v", args, result.Err)
+	}
+
+	return result.Command
+}
+
+/*---------------------*/
+
+// AssertCommand checks the name of the parsed command.
+func AssertCommand(t testing.TB, command *clapper.CommandParsed, name string) {
+	t.Helper()
+
+	if command == nil {
+		t.Fatalf("command is nil, want %q", name)
+	}
+	if command.Name != name {
+		t.Errorf("command is %q, want %q", command.Name, name)
+	}
+}
+
+// AssertFlags checks the values of the parsed flags (flags missing in `want` are not checked).
+func AssertFlags(t testing.TB, command *clapper.CommandParsed, want map[string]string) {
+	t.Helper()
+
+	for name, value := range want {
+		flag, ok := command.Flags[name]
+		if !ok {
+			t.Errorf("flag(%s) not found", name)
+		} else if flag.Value != value {
+			t.Errorf("flag(%s) is %q, want %q", name, flag.Value, value)
+		}
+	}
+}
+
+// AssertArgs checks the values of the parsed arguments (arguments missing in `want` are not checked).
+func AssertArgs(t testing.TB, command *clapper.CommandParsed, want map[string]string) {
+	t.Helper()
+
+	for name, value := range want {
+		arg, ok := command.Args[name]
+		if !ok {
+			t.Errorf("argument(%s) not found", name)
+		} else if arg.Value != value {
+			t.Errorf("argument(%s) is %q, want %q", name, arg.Value, value)
+		}
+	}
+}
+
+// AssertError checks that the error is equal to `want` (for example `clapper.ErrorUnknownFlag{Name: "-d"}`).
+func AssertError(t testing.TB, err error, want error) {
+	t.Helper()
+
+	if !reflect.DeepEqual(err, want) && !errors.Is(err, want) {
+		t.Errorf("error is %#v, want %#v", err, want)
+	}
+}
+
+// AssertErrorAs checks that the error matches the type of `target` using `errors.As`.
+// The `target` argument must be a pointer to an error type, for example `new(clapper.ErrorUnknownFlag)`.
+func AssertErrorAs(t testing.TB, err error, target interface{}) {
+	t.Helper()
+
+	if err == nil || !errors.As(err, target) {
+		t.Errorf("error is %#v, want %T", err, target)
+	}
+}
+
+/*---------------------*/
+
+// Help returns the help text of the command registered with the name and fails the test on error.
+func Help(t testing.TB, registry *clapper.Registry, name string) string {
+	t.Helper()
+
+	help, err := registry.Help(name)
+	if err != nil {
+		t.Fatalf("help for %q: %v", name, err)
+	}
+
+	return help
+}
+
+// Golden compares the content with the golden file `testdata/<name>.golden`.
+// If the test is run with the `-clappertest.update` flag, the golden file is written instead.
+func Golden(t testing.TB, name string, content string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run with -clappertest.update to create it)", err)
+	}
+	if string(want) != content {
+		t.Errorf("%s mismatch (run with -clappertest.update to update it)\ngot:\n%s\nwant:\n%s", path, content, want)
+	}
+}
@@ -0,0 +1,117 @@
+package clappertest
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/msaf1980/clapper"
+)
+
+// the messages of the tests are English regardless of the environment
+func TestMain(m *testing.M) {
+	os.Setenv("LC_ALL", "C")
+	os.Exit(m.Run())
+}
+
+// registry used by the harness tests
+func newRegistry() *clapper.Registry {
+	registry := clapper.NewRegistry()
+	registry.Name = "app"
+
+	rootCommand, _ := registry.Register("")
+	rootCommand.AddArg("file", "")
+	rootCommand.AddFlag("verbose", "v", true, "")
+
+	return registry
+}
+
+// test fixture files and environment
+func TestRunFixture(t *testing.T) {
+	const env = "CLAPPERTEST_TEST_ENV"
+	os.Unsetenv(env)
+
+	result := Run(t, newRegistry(), Fixture{
+		Args:  []string{"-v", DirPlaceholder + "/conf/app.conf"},
+		Env:   map[string]string{env: DirPlaceholder},
+		Files: map[string]string{"conf/app.conf": "content"},
+	})
+	if result.Err != nil {
+		t.Fatal(result.Err)
+	}
+
+	path := result.Command.Args["file"].Value
+	if !strings.HasPrefix(path, os.TempDir()) || !strings.HasSuffix(path, "/conf/app.conf") {
+		t.Fatalf("got path %q", path)
+	}
+
+	// fixture is removed after the run
+	if _, err := ioutil.ReadFile(path); !os.IsNotExist(err) {
+		t.Errorf("fixture file is not removed: %v", err)
+	}
+	if _, ok := os.LookupEnv(env); ok {
+		t.Errorf("environment variable %s is set", env)
+	}
+
+	AssertCommand(t, result.Command, "")
+	AssertFlags(t, result.Command, map[string]string{"verbose": "true"})
+}
+
+// test output capture
+func TestRunOutput(t *testing.T) {
+	registry := newRegistry()
+	registry.DumpArgs = true
+
+	result := Run(t, registry, Fixture{Args: []string{"--dump-args", "main.go"}})
+
+	AssertError(t, result.Err, clapper.ErrorDumpArgs{})
+	AssertErrorAs(t, result.Err, new(clapper.ErrorDumpArgs))
+	if !strings.Contains(result.Stdout, `"value": "main.go"`) || result.Stderr != "" {
+		t.Errorf("got stdout %q, stderr %q", result.Stdout, result.Stderr)
+	}
+	if registry.Stdout != nil || registry.Stderr != nil {
+		t.Errorf("registry output is not restored")
+	}
+}
+
+// test parse helper and golden files
+func TestParseAndGolden(t *testing.T) {
+	command := Parse(t, newRegistry(), "main.go")
+	AssertArgs(t, command, map[string]string{"file": "main.go"})
+
+	Golden(t, "help", Help(t, newRegistry(), ""))
+}
+
+// test the fixture environment and configuration don't change the registry and the process environment
+func TestRunIsolation(t *testing.T) {
+	const env = "CLAPPERTEST_TEST_OUTPUT"
+	os.Unsetenv(env)
+
+	registry := newRegistry()
+	output, _ := registry.Commands[""].AddFlag("output", "o", false, "")
+	output.SetEnvVar(env)
+	level, _ := registry.Commands[""].AddFlag("level", "", false, "")
+	level.SetEnvVar("APP_LEVEL")
+	registry.LookupEnv = func(name string) (string, bool) { return "debug", name == "APP_LEVEL" }
+
+	result := Run(t, registry, Fixture{
+		Args:   []string{"file"},
+		Env:    map[string]string{env: "/env"},
+		Config: map[string]string{"verbose": "true"},
+	})
+	if result.Err != nil {
+		t.Fatal(result.Err)
+	}
+	AssertFlags(t, result.Command, map[string]string{"output": "/env", "level": "debug", "verbose": "true"})
+
+	if _, ok := os.LookupEnv(env); ok {
+		t.Errorf("environment variable %s is set", env)
+	}
+	if registry.Stdout != nil || registry.Stderr != nil || registry.Configs != nil {
+		t.Errorf("registry is changed: %+v", registry)
+	}
+	if _, ok := registry.LookupEnv(env); ok {
+		t.Errorf("environment variable %s is found by the registry", env)
+	}
+}
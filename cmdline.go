@@ -0,0 +1,115 @@
+package clapper
+
+import "strings"
+
+// Split splits a command line to values using POSIX shell quoting rules
+// (single and double quotes, backslash escapes, `#` comments), no expansion is performed.
+// If a quote is not closed, it returns `ErrorUnterminatedQuote` error with the position of the quote.
+func Split(line string) ([]string, error) {
+	tokens, err := tokenize(line)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]string, len(tokens))
+	for i, t := range tokens {
+		values[i] = t.value
+	}
+
+	return values, nil
+}
+
+// ParseString method splits the command line (see `Split`) and parses the values (see `Registry.Parse`).
+// The command line doesn't contain the program name, for example `info student --output "my dir"`.
+func (registry *Registry) ParseString(line string) (*CommandParsed, error) {
+	values, err := Split(line)
+	if err != nil {
+		return nil, err
+	}
+
+	return registry.Parse(values)
+}
+
+// ParseString method splits the command line and parses the values (see `Registry.ParseString`).
+func (parser *Parser) ParseString(line string) (*CommandParsed, error) {
+	return parser.registry.ParseString(line)
+}
+
+/*---------------------*/
+
+// check if the character doesn't need quoting
+func isSafeChar(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || strings.ContainsRune("_-+=.,/:@%", r)
+}
+
+// Quote returns the value quoted for a POSIX shell (and `Split`).
+// A value without special characters is returned as is, other values are enclosed in single quotes.
+func Quote(value string) string {
+	if value == "" {
+		return "''"
+	}
+
+	if strings.IndexFunc(value, func(r rune) bool { return !isSafeChar(r) }) == -1 {
+		return value
+	}
+
+	return "'" + strings.Replace(value, "'", `'\''`, -1) + "'"
+}
+
+// Join returns the values quoted (see `Quote`) and separated by spaces.
+func Join(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = Quote(value)
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// Values method returns the command-line argument values which produce the parsed command:
+// the command name, the flags and arguments found in the command-line arguments (see `CommandParsed.Occurrences`)
+// in the canonical form (`--<flag> <value>`, `--<flag>` or `--no-<flag>` for boolean flags) and the passthrough values after `--`.
+// The unknown flags (see `CommandParsed.Unknown`) follow the command name.
+// Values of the sensitive flags are included, use `Join` to get a quoted command line.
+func (commandParsed *CommandParsed) Values() []string {
+	values := make([]string, 0, len(commandParsed.Occurrences)+len(commandParsed.Passthrough)+len(commandParsed.Unknown)+2)
+	if commandParsed.Name != "" {
+		values = append(values, commandParsed.Name)
+	}
+	values = append(values, commandParsed.Unknown...)
+
+	endOfFlags := false
+	for _, occurrence := range commandParsed.Occurrences {
+		switch {
+		case !occurrence.IsFlag:
+			if !endOfFlags && isFlag(occurrence.Value) {
+				values = append(values, "--")
+				endOfFlags = true
+			}
+			values = append(values, occurrence.Value)
+		case commandParsed.isBooleanFlag(occurrence.Name):
+			if occurrence.Value == "false" {
+				values = append(values, "--no-"+occurrence.Name)
+			} else {
+				values = append(values, "--"+occurrence.Name)
+			}
+		default:
+			values = append(values, "--"+occurrence.Name, occurrence.Value)
+		}
+	}
+
+	if len(commandParsed.Passthrough) > 0 {
+		if !endOfFlags {
+			values = append(values, "--")
+		}
+		values = append(values, commandParsed.Passthrough...)
+	}
+
+	return values
+}
+
+// check if the parsed flag is a boolean flag
+func (commandParsed *CommandParsed) isBooleanFlag(name string) bool {
+	flag, ok := commandParsed.Flags[name]
+	return ok && flag.IsBoolean
+}
@@ -0,0 +1,93 @@
+package clapper_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/msaf1980/clapper"
+	"github.com/msaf1980/clapper/clappertest"
+)
+
+// test splitting and parsing of command lines
+func TestParseString(t *testing.T) {
+	registry := newDemoRegistry(true)
+
+	command, err := registry.ParseString(`info student -o "my dir" --version=2.0.0 'math' -- science # comment`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clappertest.AssertCommand(t, command, "info")
+	clappertest.AssertFlags(t, command, map[string]string{"output": "my dir", "version": "2.0.0"})
+	clappertest.AssertArgs(t, command, map[string]string{"category": "student", "username": "math", "subjects": "science"})
+
+	// an empty value doesn't end the values
+	command, err = registry.ParseString(`info math '' -v --output "" science`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clappertest.AssertFlags(t, command, map[string]string{"verbose": "true", "output": ""})
+	clappertest.AssertArgs(t, command, map[string]string{"category": "math", "username": "", "subjects": "science"})
+
+	_, err = registry.ParseString("info \\\n --output 'my dir")
+	clappertest.AssertError(t, err, clapper.ErrorUnterminatedQuote{Quote: '\'', Line: 2, Column: 11})
+
+	values, err := clapper.Split(`a\ b "c \"d\"" '$e' ''`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a b", `c "d"`, "$e", ""}; !reflect.DeepEqual(values, want) {
+		t.Errorf("got %q, want %q", values, want)
+	}
+}
+
+// test quoting of values
+func TestQuote(t *testing.T) {
+	tests := map[string]string{
+		"":               "''",
+		"plain":          "plain",
+		"--dir=/var/a,b": "--dir=/var/a,b",
+		"my dir":         "'my dir'",
+		"it's":           `'it'\''s'`,
+		"$HOME":          "'$HOME'",
+		"a\nb":           "'a\nb'",
+	}
+
+	for value, want := range tests {
+		quoted := clapper.Quote(value)
+		if quoted != want {
+			t.Errorf("%q: got %q, want %q", value, quoted, want)
+		}
+
+		// quoted value is split back to the value
+		values, err := clapper.Split(quoted)
+		if err != nil || !reflect.DeepEqual(values, []string{value}) {
+			t.Errorf("%q: split to %q (%v)", quoted, values, err)
+		}
+	}
+}
+
+// test the command line of a parsed command
+func TestJoinParsed(t *testing.T) {
+	registry := newDemoRegistry(true)
+	registry.Commands[""].AddFlag("no-clean", "", true, "")
+
+	command := clappertest.Parse(t, registry, "-v", "out", "--dir=my dir", "--no-clean", "--dir", "*.go", "--", "-x", "extra")
+	line := clapper.Join(command.Values())
+	if want := "--verbose out --dir 'my dir' --no-clean --dir '*.go' -- -x extra"; line != want {
+		t.Errorf("got %q, want %q", line, want)
+	}
+
+	// the command line produces the same command
+	reparsed, err := registry.ParseString(line)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reparsed.String() != command.String() || !reflect.DeepEqual(reparsed.FlagValues("dir"), command.FlagValues("dir")) {
+		t.Errorf("got %q, want %q", reparsed, command)
+	}
+
+	command = clappertest.Parse(t, newDemoRegistry(false), "ghost", "--", "-v")
+	if got, want := clapper.Join(command.Values()), "ghost -- -v"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,120 @@
+package clapper
+
+import "io"
+
+// Parser type is an immutable snapshot of a registry returned by `Registry.Compile`.
+// A parser is safe for concurrent use by multiple goroutines unless its flags or arguments
+// are bound to values (see `Registry.Compile`).
+type Parser struct {
+	// private copy of the registry (never modified)
+	registry *Registry
+}
+
+// Compile method freezes the registry: it returns a parser with a deep copy of the registered commands, flags,
+// arguments and registry options. Later changes of the registry don't affect the parser.
+//
+// `Parser.Parse` can be called concurrently, each call returns a new `CommandParsed` which shares nothing
+// with the parser or other results. The options shared by the calls must be safe for concurrent use as well:
+// `LookupEnv`, `Configs` (`ConfigMap` is), `Stdout` and `Stderr` (written only with `DumpArgs`, `HelpFlags`,
+// the version flags or `PrintWarnings`) and `Stdin` (read only by `--<flag>-file -`). Prompting reads a shared input,
+// so `Prompter` should be nil for a parser used by multiple goroutines. The bound values of the flags and arguments
+// (see `FlagCommand.SetFlagValue`, `ArgCommand.SetArgValue` and `CommandConfig.ImportFlagSet`) are not copied:
+// each call sets the same variables, so a parser with bound values must not be used by multiple goroutines.
+func (registry *Registry) Compile() *Parser {
+	compiled := *registry
+	compiled.Commands = make(map[string]*CommandConfig, len(registry.Commands))
+	compiled.Configs = append([]ConfigSource(nil), registry.Configs...)
+	compiled.PluginDirs = append([]string(nil), registry.PluginDirs...)
+	if registry.Theme != nil {
+		theme := *registry.Theme
+		compiled.Theme = &theme
+	}
+
+	// commands and their aliases share the copy
+	copies := make(map[*CommandConfig]*CommandConfig, len(registry.Commands))
+	for name, commandConfig := range registry.Commands {
+		commandCopy, ok := copies[commandConfig]
+		if !ok {
+			commandCopy = commandConfig.clone()
+			copies[commandConfig] = commandCopy
+		}
+		compiled.Commands[name] = commandCopy
+	}
+
+	return &Parser{registry: &compiled}
+}
+
+// Parse method parses command-line arguments (see `Registry.Parse`).
+func (parser *Parser) Parse(values []string) (*CommandParsed, error) {
+	return parser.registry.Parse(values)
+}
+
+// Help method returns the help text of the command (see `Registry.Help`).
+func (parser *Parser) Help(name string) (string, error) {
+	return parser.registry.Help(name)
+}
+
+// WriteHelp method writes the help text of the command to the writer (see `Registry.WriteHelp`).
+func (parser *Parser) WriteHelp(w io.Writer, name string) error {
+	return parser.registry.WriteHelp(w, name)
+}
+
+// RunPlugin method runs the executable of a plugin command (see `Registry.RunPlugin`).
+func (parser *Parser) RunPlugin(command *CommandParsed) error {
+	return parser.registry.RunPlugin(command)
+}
+
+// Schema method returns the schema of the registered commands (see `Registry.Schema`).
+func (parser *Parser) Schema() *Schema {
+	return parser.registry.Schema()
+}
+
+/*---------------------*/
+
+// return a deep copy of the command
+func (commandConfig *CommandConfig) clone() *CommandConfig {
+	c := *commandConfig
+	c.Aliases = append([]string(nil), commandConfig.Aliases...)
+	c.ArgNames = append(make([]string, 0, len(commandConfig.ArgNames)), commandConfig.ArgNames...)
+	if commandConfig.Version != nil {
+		version := *commandConfig.Version
+		c.Version = &version
+	}
+
+	c.Flags = make(map[string]*FlagCommand, len(commandConfig.Flags))
+	for name, flag := range commandConfig.Flags {
+		f := *flag
+		f.ValidVals = copyValidVals(flag.ValidVals)
+		f.validValsOrder = append([]string(nil), flag.validValsOrder...)
+		c.Flags[name] = &f
+	}
+
+	c.flagsShort = make(map[string]string, len(commandConfig.flagsShort))
+	for shortName, name := range commandConfig.flagsShort {
+		c.flagsShort[shortName] = name
+	}
+
+	c.Args = make(map[string]*ArgCommand, len(commandConfig.Args))
+	for name, arg := range commandConfig.Args {
+		a := *arg
+		a.ValidVals = copyValidVals(arg.ValidVals)
+		a.validValsOrder = append([]string(nil), arg.validValsOrder...)
+		c.Args[name] = &a
+	}
+
+	return &c
+}
+
+// return a copy of the valid values
+func copyValidVals(valid map[string]bool) map[string]bool {
+	if valid == nil {
+		return nil
+	}
+
+	c := make(map[string]bool, len(valid))
+	for v, ok := range valid {
+		c[v] = ok
+	}
+
+	return c
+}
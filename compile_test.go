@@ -0,0 +1,95 @@
+package clapper_test
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/msaf1980/clapper"
+)
+
+// test that a compiled parser is not affected by the registry changes
+func TestCompile(t *testing.T) {
+	registry := newDemoRegistry(false)
+	registry.RegisterAlias("info", "information")
+	parser := registry.Compile()
+
+	// modify the registry after the compilation
+	infoCommand := registry.Commands["info"]
+	infoCommand.AddFlag("extra", "x", true, "")
+	infoCommand.Flags["output"].DefaultValue = "/changed"
+	infoCommand.Flags["output"].SetValidVals([]string{"/changed"})
+	registry.Register("new")
+
+	command, err := parser.Parse([]string{"information", "student"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := command.Flags["output"].Value; got != "./" {
+		t.Errorf("got output %q", got)
+	}
+	if _, ok := command.Flags["extra"]; ok {
+		t.Errorf("got flag added after the compilation")
+	}
+	if _, err := parser.Parse([]string{"new"}); !reflect.DeepEqual(err, clapper.ErrorUnknownCommand{Name: "new"}) {
+		t.Errorf("got error %#v", err)
+	}
+
+	compiled := parser.Schema()
+	for _, command := range compiled.Commands {
+		if command.Name == "new" {
+			t.Errorf("got command registered after the compilation")
+		}
+	}
+}
+
+// test concurrent parsing (run with -race)
+func TestParserConcurrent(t *testing.T) {
+	registry := newDemoRegistry(true)
+	registry.RegisterAlias("info", "information")
+	parser := registry.Compile()
+
+	inputs := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"info", "student", "-v", "--output", "./tmp"}, "student"},
+		{[]string{"info", "manager", "--no-clean"}, "manager"},
+		{[]string{"information", "student", "math", "science"}, "student"},
+		{[]string{"info"}, "manager"},
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 64)
+	for i := 0; i < 64; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			input := inputs[i%len(inputs)]
+			command, err := parser.Parse(input.args)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if got := command.Args["category"].Value; got != input.want {
+				errs <- fmt.Errorf("%q: got category %q, want %q", input.args, got, input.want)
+				return
+			}
+
+			// results are independent
+			command.Args["category"].Value = "changed"
+			command.Flags["output"].Value = "changed"
+		}(i)
+	}
+
+	// the registry can be modified while the parser is used
+	registry.Commands["info"].AddFlag("extra", "x", true, "")
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,144 @@
+package clapper
+
+import (
+	"sort"
+	"strings"
+)
+
+// Complete method returns the sorted candidates completing the `toComplete` value after the `values`
+// (the command-line argument values without the program name): command names, flag names
+// and the valid values of flags and arguments (see `FlagCommand.SetValidVals`) and plugin command names (see `Registry.Plugins`).
+// Hidden, deprecated and replaced commands and flags are not completed.
+func (registry *Registry) Complete(values []string, toComplete string) []string {
+	candidates := make([]string, 0)
+
+	// complete the command name
+	if (len(values) == 0 || (registry.SkipLeadingFlags && registry.leadingFlags(values) == len(values))) && !isFlag(toComplete) {
+		for _, name := range registry.visibleCommandNames() {
+			if name != "" {
+				candidates = append(candidates, name)
+				candidates = append(candidates, registry.Commands[name].Aliases...)
+			}
+		}
+		candidates = append(candidates, registry.PluginNames()...)
+	}
+
+	// get the command of the values
+	var commandConfig *CommandConfig
+	if name, index, err := registry.resolveCommand(values); err == nil {
+		commandConfig = registry.Commands[name]
+		if index != -1 {
+			values = append(append(make([]string, 0, len(values)-1), values[:index]...), values[index+1:]...)
+		}
+	}
+
+	if commandConfig != nil && (commandConfig.Name == "" || !commandConfig.isHidden()) {
+		candidates = append(candidates, commandConfig.complete(values, toComplete)...)
+	}
+
+	return filterCandidates(candidates, toComplete)
+}
+
+// return the candidates completing the value after the values of the command
+func (commandConfig *CommandConfig) complete(values []string, toComplete string) []string {
+	candidates := make([]string, 0)
+
+	// count the positional values and find the flag expecting a value
+	positional := 0
+	endOfFlags := false
+	var valueFlag *FlagCommand
+	for _, value := range values {
+		switch {
+		case valueFlag != nil:
+			valueFlag = nil
+		case endOfFlags || !isFlag(value):
+			positional++
+		case isEndOfFlags(value):
+			endOfFlags = true
+		case !strings.Contains(value, "="):
+			if flag := commandConfig.lookupFlag(value); flag != nil && !flag.IsBoolean {
+				valueFlag = flag
+			}
+		}
+	}
+
+	// complete the value of a flag (valid values of a sensitive flag are not revealed)
+	if valueFlag != nil {
+		if valueFlag.Sensitive {
+			return candidates
+		}
+		return valueFlag.ValidValsList()
+	}
+	if !endOfFlags && isFlag(toComplete) && strings.Contains(toComplete, "=") {
+		name := toComplete[:strings.Index(toComplete, "=")]
+		if flag := commandConfig.lookupFlag(name); flag != nil && !flag.IsBoolean && !flag.Sensitive {
+			for _, v := range flag.ValidValsList() {
+				candidates = append(candidates, name+"="+v)
+			}
+		}
+		return candidates
+	}
+
+	// complete the flag names
+	if !endOfFlags && strings.HasPrefix(toComplete, "-") {
+		for _, name := range commandConfig.visibleFlagNames() {
+			flag := commandConfig.Flags[name]
+			if flag.IsInverted {
+				candidates = append(candidates, "--no-"+flag.Name)
+			} else {
+				candidates = append(candidates, "--"+flag.Name)
+			}
+			if flag.sensitiveFile() {
+				candidates = append(candidates, "--"+flag.Name+SensitiveFileSuffix)
+			}
+		}
+		return candidates
+	}
+
+	// complete the value of an argument (the value after a variadic argument can be a value of any argument after it)
+	for index, name := range commandConfig.ArgNames {
+		arg := commandConfig.Args[name]
+		if index >= positional || arg.IsVariadic {
+			candidates = append(candidates, arg.ValidValsList()...)
+		}
+		if index >= positional {
+			break
+		}
+	}
+
+	return candidates
+}
+
+// return the registered flag of a flag value (`-s`, `--name` or `--no-name`)
+func (commandConfig *CommandConfig) lookupFlag(value string) *FlagCommand {
+	if isShortFlag(value) {
+		if name, ok := commandConfig.flagsShort[strings.TrimPrefix(value, "-")]; ok {
+			return commandConfig.Flags[name]
+		}
+		return nil
+	}
+
+	if ok, name := isInvertedFlag(value); ok {
+		return commandConfig.Flags[name]
+	}
+	if flag, ok := commandConfig.Flags[strings.TrimPrefix(value, "--")]; ok {
+		return flag
+	}
+
+	return commandConfig.sensitiveFileFlag(strings.TrimPrefix(value, "--"))
+}
+
+// return the unique non-empty candidates with the prefix in sorted order
+func filterCandidates(candidates []string, prefix string) []string {
+	filtered := make([]string, 0, len(candidates))
+	seen := make(map[string]bool, len(candidates))
+	for _, candidate := range candidates {
+		if candidate != "" && !seen[candidate] && strings.HasPrefix(candidate, prefix) {
+			seen[candidate] = true
+			filtered = append(filtered, candidate)
+		}
+	}
+	sort.Strings(filtered)
+
+	return filtered
+}
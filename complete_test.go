@@ -0,0 +1,48 @@
+package clapper_test
+
+import (
+	"reflect"
+	"testing"
+)
+
+// test completion of the command-line argument values
+func TestComplete(t *testing.T) {
+	registry := newDemoRegistry(true)
+	registry.RegisterAlias("info", "information")
+	registry.Register("secret")
+	registry.Commands["secret"].SetHidden(true)
+	infoCommand := registry.Commands["info"]
+	infoCommand.AddFlag("old", "", false, "")
+	infoCommand.Flags["old"].SetDeprecated("it has no effect")
+	token, _ := infoCommand.AddFlagWithValid("token", "", false, "", []string{"s3cr3t"})
+	token.SetSensitive(true)
+
+	tests := []struct {
+		values     []string
+		toComplete string
+		want       []string
+	}{
+		{nil, "", []string{"ghost", "info", "information"}},
+		{nil, "in", []string{"info", "information"}},
+		{nil, "--v", []string{"--verbose", "--version"}},
+		{[]string{"info"}, "--", []string{"--no-clean", "--output", "--token", "--token-file", "--verbose", "--version"}},
+		{[]string{"info"}, "--no", []string{"--no-clean"}},
+		{[]string{"info"}, "s", []string{"science", "student"}},
+		{[]string{"info", "--version"}, "", []string{"1.0.1", "2.0.0"}},
+		{[]string{"info", "-V"}, "1", []string{"1.0.1"}},
+		{[]string{"info"}, "--version=2", []string{"--version=2.0.0"}},
+		{[]string{"info", "--token"}, "", []string{}},
+		{[]string{"info"}, "--token=", []string{}},
+		{[]string{"info", "-v", "student", "-o", "./"}, "", []string{}},
+		{[]string{"info", "--", "student", "me"}, "-", []string{}},
+		{[]string{"secret"}, "", []string{}},
+		{[]string{"unknown"}, "", []string{}},
+	}
+
+	for _, tt := range tests {
+		got := registry.Complete(tt.values, tt.toComplete)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q %q: got %q, want %q", tt.values, tt.toComplete, got, tt.want)
+		}
+	}
+}
@@ -11,6 +11,7 @@ func main() {
 
 	// create a new registry
 	registry := clapper.NewRegistry()
+	registry.DumpArgs = true // enable hidden `--dump-args` flag
 
 	// register the root command
 	if _, ok := os.LookupEnv("NO_ROOT"); !ok {
@@ -45,7 +46,9 @@ func main() {
 	/*----------------*/
 
 	// check for error
-	if err != nil {
+	if _, ok := err.(clapper.ErrorDumpArgs); ok {
+		return // parsed arguments are already printed in JSON format
+	} else if err != nil {
 		fmt.Printf("error => %#v\n", err)
 		return
 	}
@@ -53,9 +56,9 @@ func main() {
 	// get executed sub-command name
 	fmt.Printf("sub-command => %#v\n", command.Name)
 
-	// get argument values
-	for argName, argValue := range command.Args {
-		fmt.Printf("argument(%s) => %#v\n", argName, argValue)
+	// get argument values (in registration order)
+	for _, argName := range command.ArgNames {
+		fmt.Printf("argument(%s) => %#v\n", argName, command.Args[argName])
 	}
 
 	// get flag values
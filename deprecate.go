@@ -0,0 +1,113 @@
+package clapper
+
+import (
+	"io"
+	"os"
+)
+
+// return the writer for the diagnostic messages of the registry
+func (registry *Registry) stderr() io.Writer {
+	if registry.Stderr == nil {
+		return os.Stderr
+	}
+	return registry.Stderr
+}
+
+// format a deprecation warning in the language of the registry, `key` is the message of the deprecated command or flag
+func (registry *Registry) deprecationWarning(key string, name string, replacedBy string, message string) string {
+	messages := registry.messages()
+	warning := messages.format(key, name)
+	if replacedBy != "" {
+		warning = messages.format("deprecated.replacedBy", warning, replacedBy)
+	}
+	if message != "" {
+		warning = messages.format("deprecated.message", warning, messages.text(message))
+	}
+	return warning
+}
+
+// return the command replacing a deprecated command and collect a deprecation warning
+func (registry *Registry) replaceCommand(commandConfig *CommandConfig, store *CommandParsed) *CommandConfig {
+	if commandConfig.ReplacedBy != "" {
+		if replacement, ok := registry.Commands[commandConfig.ReplacedBy]; ok {
+			store.Warnings = append(store.Warnings, registry.deprecationWarning("deprecated.command", commandConfig.Name, replacement.Name, commandConfig.Deprecated))
+			return replacement
+		}
+	}
+
+	if commandConfig.Deprecated != "" {
+		store.Warnings = append(store.Warnings, registry.deprecationWarning("deprecated.command", commandConfig.Name, "", commandConfig.Deprecated))
+	}
+
+	return commandConfig
+}
+
+// return the flag replacing a deprecated flag and collect a deprecation warning
+func (registry *Registry) replaceFlag(commandConfig *CommandConfig, flag *FlagCommand, store *CommandParsed) *FlagCommand {
+	if flag.ReplacedBy != "" {
+		if replacement, ok := commandConfig.Flags[flag.ReplacedBy]; ok {
+			store.Warnings = append(store.Warnings, registry.deprecationWarning("deprecated.flag", "--"+flag.Name, "--"+replacement.Name, flag.Deprecated))
+			return replacement
+		}
+	}
+
+	if flag.Deprecated != "" {
+		store.Warnings = append(store.Warnings, registry.deprecationWarning("deprecated.flag", "--"+flag.Name, "", flag.Deprecated))
+	}
+
+	return flag
+}
+
+/*---------------------*/
+
+// SetDeprecated marks the command as deprecated with the message.
+// A deprecated command is accepted, but a warning is collected in `CommandParsed.Warnings` and it is omitted from the help.
+func (commandConfig *CommandConfig) SetDeprecated(message string) *CommandConfig {
+	commandConfig.Deprecated = message
+	return commandConfig
+}
+
+// SetReplacedBy marks the command as deprecated and replaced by the command registered with the `name`.
+// The command is parsed as the replacement command (`CommandParsed.Name` is the name of the replacement),
+// a warning is collected in `CommandParsed.Warnings` and it is omitted from the help.
+func (commandConfig *CommandConfig) SetReplacedBy(name string) *CommandConfig {
+	commandConfig.ReplacedBy = removeWhitespaces(name)
+	return commandConfig
+}
+
+// SetHidden sets if the command is omitted from the help and completion.
+func (commandConfig *CommandConfig) SetHidden(hidden bool) *CommandConfig {
+	commandConfig.Hidden = hidden
+	return commandConfig
+}
+
+// check if the command is omitted from the help and completion
+func (commandConfig *CommandConfig) isHidden() bool {
+	return commandConfig.Hidden || commandConfig.Deprecated != "" || commandConfig.ReplacedBy != ""
+}
+
+// SetDeprecated marks the flag as deprecated with the message.
+// A deprecated flag is accepted, but a warning is collected in `CommandParsed.Warnings` and it is omitted from the help.
+func (f *FlagCommand) SetDeprecated(message string) *FlagCommand {
+	f.Deprecated = message
+	return f
+}
+
+// SetReplacedBy marks the flag as deprecated and replaced by the flag registered with the `name` (without `--` prefix).
+// The value of the flag is parsed by the rules of the replacement flag and stored with its name,
+// a warning is collected in `CommandParsed.Warnings` and the flag is omitted from the help and the parsed flags.
+func (f *FlagCommand) SetReplacedBy(name string) *FlagCommand {
+	f.ReplacedBy = removeWhitespaces(name)
+	return f
+}
+
+// SetHidden sets if the flag is omitted from the help and completion.
+func (f *FlagCommand) SetHidden(hidden bool) *FlagCommand {
+	f.Hidden = hidden
+	return f
+}
+
+// check if the flag is omitted from the help and completion
+func (f *FlagCommand) isHidden() bool {
+	return f.Hidden || f.Deprecated != "" || f.ReplacedBy != ""
+}
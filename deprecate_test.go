@@ -0,0 +1,98 @@
+package clapper_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/msaf1980/clapper/clappertest"
+)
+
+// test deprecated and replaced flags
+func TestDeprecatedFlags(t *testing.T) {
+	registry := newDemoRegistry(true)
+	rootCommand := registry.Commands[""]
+	path, _ := rootCommand.AddFlag("path", "p", false, "")
+	path.SetReplacedBy("dir")
+	rootCommand.Flags["force"].SetDeprecated("it has no effect")
+	rootCommand.Flags["verbose"].SetHidden(true)
+
+	result := clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"--path", "./out", "-f", "--verbose"}})
+	if result.Err != nil {
+		t.Fatal(result.Err)
+	}
+
+	clappertest.AssertFlags(t, result.Command, map[string]string{
+		"dir":     "./out",
+		"force":   "true",
+		"verbose": "true",
+	})
+	if _, ok := result.Command.Flags["path"]; ok {
+		t.Errorf("replaced flag is parsed: %v", result.Command)
+	}
+
+	want := []string{
+		"flag --path is deprecated, use --dir instead",
+		"flag --force is deprecated: it has no effect",
+	}
+	if !reflect.DeepEqual(result.Command.Warnings, want) {
+		t.Errorf("got warnings %q, want %q", result.Command.Warnings, want)
+	}
+	if result.Stderr != "" {
+		t.Errorf("warnings are printed: %q", result.Stderr)
+	}
+
+	// short name of a replaced flag
+	registry.PrintWarnings = true
+	result = clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"-p", "./out"}})
+	clappertest.AssertFlags(t, result.Command, map[string]string{"dir": "./out"})
+	if result.Stderr != "warning: flag --path is deprecated, use --dir instead\n" {
+		t.Errorf("got stderr %q", result.Stderr)
+	}
+
+	// the warnings are translated
+	registry.Locale = "ru"
+	result = clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"-p", "./out", "-f"}})
+	if result.Stderr != "предупреждение: флаг --path устарел, используйте --dir\nпредупреждение: флаг --force устарел: it has no effect\n" {
+		t.Errorf("got stderr %q", result.Stderr)
+	}
+
+	help := clappertest.Help(t, registry, "")
+	for _, hidden := range []string{"--path", "--force", "--verbose"} {
+		if strings.Contains(help, hidden) {
+			t.Errorf("%s is not hidden in\n%s", hidden, help)
+		}
+	}
+	if !strings.Contains(help, "--dir") {
+		t.Errorf("--dir is hidden in\n%s", help)
+	}
+}
+
+// test replaced and hidden commands
+func TestDeprecatedCommands(t *testing.T) {
+	registry := newDemoRegistry(true)
+	registry.Commands["ghost"].SetReplacedBy("info")
+	internal, _ := registry.Register("internal")
+	internal.SetHidden(true)
+
+	command := clappertest.Parse(t, registry, "ghost")
+	clappertest.AssertCommand(t, command, "info")
+	if want := []string{"command ghost is deprecated, use info instead"}; !reflect.DeepEqual(command.Warnings, want) {
+		t.Errorf("got warnings %q, want %q", command.Warnings, want)
+	}
+
+	command = clappertest.Parse(t, registry, "internal")
+	clappertest.AssertCommand(t, command, "internal")
+
+	help := clappertest.Help(t, registry, "")
+	for _, hidden := range []string{"ghost", "internal"} {
+		if strings.Contains(help, hidden) {
+			t.Errorf("%s is not hidden in\n%s", hidden, help)
+		}
+	}
+	for _, visible := range []string{"info", "--dir"} {
+		if !strings.Contains(help, visible) {
+			t.Errorf("%s is hidden in\n%s", visible, help)
+		}
+	}
+}
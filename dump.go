@@ -0,0 +1,128 @@
+package clapper
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// ParsedJSON type is the JSON representation of `CommandParsed`.
+//
+//	{
+//	  "command": ["info"],
+//	  "flags": {
+//	    "verbose": {"value": "true", "source": "argv", "isBoolean": true},
+//	    "output": {"value": "./", "source": "default"}
+//	  },
+//	  "args": {
+//	    "category": {"value": "student", "source": "argv"},
+//	    "subjects": {"value": "math,science", "source": "argv", "isVariadic": true}
+//	  },
+//	  "occurrences": [
+//	    {"name": "category", "value": "student", "index": 1, "raw": "student"},
+//	    {"name": "verbose", "isFlag": true, "value": "true", "index": 2, "raw": "-v"}
+//	  ],
+//	  "passthrough": ["--raw"],
+//	  "unknown": ["--color", "auto"],
+//	  "warnings": ["flag --dir is deprecated, use --output instead"]
+//	}
+//
+// The "command" field is the path of the command names (empty for the root command).
+// The "source" field is one of the `ValueSource` values, the "sourceName" field is `Flag.SourceName`.
+// The value of a sensitive flag is replaced with `RedactedValue`.
+type ParsedJSON struct {
+	Command     []string                   `json:"command"`
+	Flags       map[string]*ParsedFlagJSON `json:"flags"`
+	Args        map[string]*ParsedArgJSON  `json:"args"`
+	Occurrences []*ParsedOccurrenceJSON    `json:"occurrences"`
+	Passthrough []string                   `json:"passthrough"`
+	Unknown     []string                   `json:"unknown,omitempty"`
+	Warnings    []string                   `json:"warnings,omitempty"`
+}
+
+// ParsedFlagJSON type is the JSON representation of a parsed flag.
+type ParsedFlagJSON struct {
+	Value      string      `json:"value"`
+	Source     ValueSource `json:"source"`
+	SourceName string      `json:"sourceName,omitempty"`
+	IsBoolean  bool        `json:"isBoolean,omitempty"`
+	Sensitive  bool        `json:"sensitive,omitempty"`
+}
+
+// ParsedArgJSON type is the JSON representation of a parsed argument.
+type ParsedArgJSON struct {
+	Value      string      `json:"value"`
+	Source     ValueSource `json:"source"`
+	IsVariadic bool        `json:"isVariadic,omitempty"`
+}
+
+// ParsedOccurrenceJSON type is the JSON representation of an occurrence (see `Occurrence`).
+type ParsedOccurrenceJSON struct {
+	Name   string `json:"name"`
+	IsFlag bool   `json:"isFlag,omitempty"`
+	Value  string `json:"value"`
+	Index  int    `json:"index"`
+	Raw    string `json:"raw"`
+}
+
+// JSON method returns the JSON representation of the parsed command.
+func (commandParsed *CommandParsed) JSON() *ParsedJSON {
+	parsed := &ParsedJSON{
+		Command:     make([]string, 0, 1),
+		Flags:       make(map[string]*ParsedFlagJSON, len(commandParsed.Flags)),
+		Args:        make(map[string]*ParsedArgJSON, len(commandParsed.Args)),
+		Occurrences: make([]*ParsedOccurrenceJSON, 0, len(commandParsed.Occurrences)),
+		Passthrough: append(make([]string, 0, len(commandParsed.Passthrough)), commandParsed.Passthrough...),
+		Unknown:     append([]string(nil), commandParsed.Unknown...),
+		Warnings:    append([]string(nil), commandParsed.Warnings...),
+	}
+
+	if commandParsed.Name != "" {
+		parsed.Command = append(parsed.Command, commandParsed.Name)
+	}
+
+	for name, flag := range commandParsed.Flags {
+		parsed.Flags[name] = &ParsedFlagJSON{
+			Value:      flag.String(),
+			Source:     flag.Source,
+			SourceName: flag.SourceName,
+			IsBoolean:  flag.IsBoolean,
+			Sensitive:  flag.Sensitive,
+		}
+	}
+
+	for name, arg := range commandParsed.Args {
+		parsed.Args[name] = &ParsedArgJSON{
+			Value:      arg.Value,
+			Source:     arg.Source,
+			IsVariadic: arg.IsVariadic,
+		}
+	}
+
+	for _, occurrence := range commandParsed.Occurrences {
+		value := occurrence.Value
+		if occurrence.Sensitive && value != "" {
+			value = RedactedValue
+		}
+		parsed.Occurrences = append(parsed.Occurrences, &ParsedOccurrenceJSON{
+			Name:   occurrence.Name,
+			IsFlag: occurrence.IsFlag,
+			Value:  value,
+			Index:  occurrence.Index,
+			Raw:    occurrence.String(),
+		})
+	}
+
+	return parsed
+}
+
+// MarshalJSON method implements `json.Marshaler` interface (see `ParsedJSON` for the format).
+func (commandParsed *CommandParsed) MarshalJSON() ([]byte, error) {
+	return json.Marshal(commandParsed.JSON())
+}
+
+// Dump method writes the JSON representation of the parsed command to the writer.
+func (commandParsed *CommandParsed) Dump(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(commandParsed.JSON())
+}
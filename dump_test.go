@@ -0,0 +1,113 @@
+package clapper
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// registry used by the dump tests
+func dumpTestRegistry() *Registry {
+	registry := NewRegistry()
+
+	infoCommand, _ := registry.Register("info")
+	infoCommand.AddArg("category", "manager")
+	infoCommand.AddArg("subjects...", "")
+	infoCommand.AddFlag("verbose", "v", true, "")
+	infoCommand.AddFlag("output", "o", false, "./")
+
+	registry.Register("ghost")
+
+	return registry
+}
+
+// test JSON representation of the parsed command
+func TestParsedJSON(t *testing.T) {
+	command, err := dumpTestRegistry().Parse([]string{"info", "student", "-v", "--", "-o", "math"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(command)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got ParsedJSON
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := ParsedJSON{
+		Command: []string{"info"},
+		Flags: map[string]*ParsedFlagJSON{
+			"verbose": {Value: "true", Source: SourceArgv, IsBoolean: true},
+			"output":  {Value: "./", Source: SourceDefault},
+		},
+		Args: map[string]*ParsedArgJSON{
+			"category": {Value: "student", Source: SourceArgv},
+			"subjects": {Value: "-o,math", Source: SourceArgv, IsVariadic: true},
+		},
+		Occurrences: []*ParsedOccurrenceJSON{
+			{Name: "category", Value: "student", Index: 1, Raw: "student"},
+			{Name: "verbose", IsFlag: true, Value: "true", Index: 2, Raw: "-v"},
+			{Name: "subjects", Value: "-o", Index: 4, Raw: "-o"},
+			{Name: "subjects", Value: "math", Index: 5, Raw: "math"},
+		},
+		Passthrough: []string{},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got\n%s", data)
+	}
+}
+
+// test values after `--` without free arguments
+func TestPassthrough(t *testing.T) {
+	command, err := dumpTestRegistry().Parse([]string{"ghost", "--", "-v", "--x=1", "extra"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"-v", "--x=1", "extra"}; !reflect.DeepEqual(command.Passthrough, want) {
+		t.Fatalf("got %q, want %q", command.Passthrough, want)
+	}
+	if got := command.JSON().Command; !reflect.DeepEqual(got, []string{"ghost"}) {
+		t.Fatalf("got command path %q", got)
+	}
+}
+
+// test the hidden `--dump-args` flag
+func TestDumpArgs(t *testing.T) {
+	registry := dumpTestRegistry()
+
+	// disabled by default
+	if _, err := registry.Parse([]string{"info", "--dump-args"}); err != (ErrorUnknownFlag{"--dump-args"}) {
+		t.Fatalf("got %#v", err)
+	}
+
+	var buf bytes.Buffer
+	registry.DumpArgs = true
+	registry.Stdout = &buf
+
+	command, err := registry.Parse([]string{"info", "--dump-args", "student"})
+	if command != nil || err != (ErrorDumpArgs{}) {
+		t.Fatalf("got %#v, %#v", command, err)
+	}
+
+	var got ParsedJSON
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Args["category"].Value != "student" || got.Flags["verbose"].Source != SourceDefault {
+		t.Fatalf("got\n%s", buf.String())
+	}
+
+	// a registered flag takes precedence
+	infoCommand := registry.Commands["info"]
+	infoCommand.AddFlag(DumpArgsFlag, "", true, "")
+	buf.Reset()
+	if command, err = registry.Parse([]string{"info", "--dump-args"}); err != nil || command.Flags[DumpArgsFlag].Value != "true" || buf.Len() != 0 {
+		t.Fatalf("got %#v, %#v", command, err)
+	}
+}
@@ -0,0 +1,12 @@
+//go:build darwin || freebsd
+// +build darwin freebsd
+
+package clapper
+
+import "syscall"
+
+// ioctl requests of the terminal state
+const (
+	ioctlGetTermios = syscall.TIOCGETA
+	ioctlSetTermios = syscall.TIOCSETA
+)
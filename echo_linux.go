@@ -0,0 +1,9 @@
+package clapper
+
+import "syscall"
+
+// ioctl requests of the terminal state
+const (
+	ioctlGetTermios = syscall.TCGETS
+	ioctlSetTermios = syscall.TCSETS
+)
@@ -0,0 +1,33 @@
+//go:build !linux && !darwin && !freebsd
+// +build !linux,!darwin,!freebsd
+
+package clapper
+
+import (
+	"errors"
+	"os"
+)
+
+// check if file is a terminal (a character device on this platform)
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+// disable the echo of the terminal (not supported on this platform)
+func disableEcho(f *os.File) (func(), error) {
+	return nil, errors.New("hidden input is not supported on this platform, set Prompter.HideInput")
+}
+
+// disable the echo and the line buffering of the terminal (not supported on this platform)
+func enableRawMode(f *os.File) (func(), error) {
+	return nil, errors.New("line editing is not supported on this platform")
+}
+
+// return the number of columns of the terminal (not supported on this platform)
+func terminalWidth(f *os.File) int {
+	return 0
+}
@@ -0,0 +1,72 @@
+//go:build linux || darwin || freebsd
+// +build linux darwin freebsd
+
+package clapper
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// check if file is a terminal (its terminal state can be read)
+func isTerminal(f *os.File) bool {
+	var state syscall.Termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), ioctlGetTermios, uintptr(unsafe.Pointer(&state)))
+	return errno == 0
+}
+
+// disable the echo of the terminal, returns a function restoring the terminal state
+func disableEcho(f *os.File) (func(), error) {
+	fd := f.Fd()
+
+	var state syscall.Termios
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlGetTermios, uintptr(unsafe.Pointer(&state))); errno != 0 {
+		return nil, errno
+	}
+
+	noEcho := state
+	noEcho.Lflag &^= syscall.ECHO
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlSetTermios, uintptr(unsafe.Pointer(&noEcho))); errno != 0 {
+		return nil, errno
+	}
+
+	return func() {
+		syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlSetTermios, uintptr(unsafe.Pointer(&state)))
+	}, nil
+}
+
+// disable the echo and the line buffering of the terminal (bytes are read as typed, signals are not generated),
+// returns a function restoring the terminal state
+func enableRawMode(f *os.File) (func(), error) {
+	fd := f.Fd()
+
+	var state syscall.Termios
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlGetTermios, uintptr(unsafe.Pointer(&state))); errno != 0 {
+		return nil, errno
+	}
+
+	raw := state
+	raw.Lflag &^= syscall.ECHO | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Iflag &^= syscall.ICRNL | syscall.IXON
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlSetTermios, uintptr(unsafe.Pointer(&raw))); errno != 0 {
+		return nil, errno
+	}
+
+	return func() {
+		syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlSetTermios, uintptr(unsafe.Pointer(&state)))
+	}, nil
+}
+
+// return the number of columns of the terminal, 0 if it is unknown
+func terminalWidth(f *os.File) int {
+	var size struct {
+		rows, columns, xpixel, ypixel uint16
+	}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&size))); errno != 0 {
+		return 0
+	}
+	return int(size.columns)
+}
@@ -0,0 +1,216 @@
+package clapper
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// exit codes of `Registry.HandleError` (see sysexits(3))
+const (
+	// ExitSuccess is the exit code of a successful run, `--help`, `--version` and `--dump-args`.
+	ExitSuccess = 0
+
+	// ExitFailure is the exit code of the other errors.
+	ExitFailure = 1
+
+	// ExitUsage is the exit code of the command-line usage errors (EX_USAGE).
+	ExitUsage = 64
+
+	// ExitDataErr is the exit code of the invalid input data, for example a response file including itself (EX_DATAERR).
+	ExitDataErr = 65
+
+	// ExitNoInput is the exit code of an input file which can't be read (EX_NOINPUT).
+	ExitNoInput = 66
+
+	// ExitConfig is the exit code of the configuration errors (EX_CONFIG).
+	ExitConfig = 78
+)
+
+// ExitCoder interface is implemented by the errors with an exit code, for example `ErrorExit` or `*exec.ExitError`.
+type ExitCoder interface {
+	ExitCode() int
+}
+
+// ErrorExit represents an error with a custom exit code returned by an action (see `Registry.Run`).
+// If the `Err` field is nil, the exit code is returned without a message.
+type ErrorExit struct {
+	Code int
+	Err  error
+}
+
+func (e ErrorExit) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+// Unwrap method returns the wrapped error.
+func (e ErrorExit) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode method implements `ExitCoder` interface.
+func (e ErrorExit) ExitCode() int {
+	return e.Code
+}
+
+// ErrorHelp is returned by `Registry.Parse` when the `--help` or `-h` flag is found in the arguments
+// (see `Registry.HelpFlags`). The help of the command is written to `Registry.Stdout` before the error is returned.
+type ErrorHelp struct {
+	Command string
+}
+
+func (e ErrorHelp) Error() string {
+	return "help requested"
+}
+
+/*---------------------*/
+
+// ExitCode returns the exit code of an error: `ExitSuccess` for nil, `ErrorHelp`, `ErrorVersion` and `ErrorDumpArgs` errors,
+// the code of an `ExitCoder` error (`ExitFailure` for a negative code), `ExitUsage` for the command-line usage errors,
+// `ExitDataErr`, `ExitNoInput` or `ExitConfig` for the errors of the input files and `ExitFailure` for other errors.
+// The wrapped errors are checked from the outermost one.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitSuccess
+	}
+
+	for ; err != nil; err = errors.Unwrap(err) {
+		if coder, ok := err.(ExitCoder); ok {
+			if code := coder.ExitCode(); code >= 0 {
+				return code
+			}
+			return ExitFailure
+		}
+
+		switch err.(type) {
+		case ErrorHelp, ErrorVersion, ErrorDumpArgs:
+			return ExitSuccess
+		case ErrorUnknownCommand, ErrorUnknownFlag, ErrorUnsupportedFlag, ErrorMissingFlag, ErrorMissingArg,
+			ErrorUnsupportedValue, ErrorFlagValue, ErrorArgValue, ErrorMisplacedArg, ErrorAmbiguousCommand, ErrorUnterminatedQuote:
+			return ExitUsage
+		case ErrorResponseFileCycle, ErrorResponseFileDepth:
+			return ExitDataErr
+		case ErrorResponseFile, ErrorSensitiveFile:
+			return ExitNoInput
+		case ErrorConfigFile, ErrorSchemaVersion, ErrorSchemaInvalid:
+			return ExitConfig
+		}
+	}
+
+	return ExitFailure
+}
+
+// check if the value is the `--help` or `-h` flag of the command (unless the command registers them)
+func (registry *Registry) isHelpFlag(commandConfig *CommandConfig, value string) bool {
+	if !registry.HelpFlags {
+		return false
+	}
+
+	switch value {
+	case "--help":
+		_, ok := commandConfig.Flags["help"]
+		return !ok
+	case "-h":
+		_, ok := commandConfig.flagsShort["h"]
+		return !ok
+	}
+
+	return false
+}
+
+// format the names of the built-in flags, "" if both are disabled or registered by the command
+func builtinFlagUsage(long string, hasLong bool, short string, hasShort bool) string {
+	switch {
+	case hasLong && hasShort:
+		return "-" + short + ", --" + long
+	case hasLong:
+		return "    --" + long
+	case hasShort:
+		return "-" + short
+	}
+	return ""
+}
+
+// format the names of the help flags of the command
+func (registry *Registry) helpFlagUsage(commandConfig *CommandConfig) string {
+	return builtinFlagUsage("help", registry.isHelpFlag(commandConfig, "--help"), "h", registry.isHelpFlag(commandConfig, "-h"))
+}
+
+// HandleError method writes the message of an error to `Registry.Stderr` and returns its exit code (see `ExitCode`).
+// The message of a command-line usage error is followed by the usage line of the root command
+// (and a hint to use `--help` if `Registry.HelpFlags` is enabled). Nothing is written for `ErrorHelp`,
+// `ErrorVersion` and `ErrorDumpArgs` errors and `ErrorExit` errors without a wrapped error.
+func (registry *Registry) HandleError(err error) int {
+	return registry.handleError(err, "")
+}
+
+// write the message of an error with the usage of the command
+func (registry *Registry) handleError(err error, commandName string) int {
+	code := ExitCode(err)
+	if err == nil || code == ExitSuccess {
+		return code
+	}
+	if exit, ok := err.(ErrorExit); ok && exit.Err == nil {
+		return code
+	}
+
+	o := registry.output(registry.stderr())
+	fmt.Fprintf(registry.stderr(), "%s: %s\n", registry.programName(), o.paint(o.theme.Error, registry.ErrorMessage(err)))
+	if code != ExitUsage {
+		return code
+	}
+
+	if _, ok := registry.Commands[commandName]; !ok {
+		commandName = ""
+	}
+	if help, err := registry.help(commandName, o); err == nil {
+		fmt.Fprintln(registry.stderr(), strings.SplitN(help, "\n", 2)[0])
+	}
+	if registry.HelpFlags {
+		name := registry.programName()
+		if commandName != "" {
+			name += " " + commandName
+		}
+		fmt.Fprintln(registry.stderr(), registry.messages().format("hint.help", name))
+	}
+
+	return code
+}
+
+// Run method parses the command-line arguments (without the program name), runs a plugin command
+// (see `Registry.RunPlugin`) or calls the action with the parsed command and handles its error (see `Registry.HandleError`).
+// It returns the exit code, the usage hint of a command-line usage error is for the command of the arguments.
+func (registry *Registry) Run(values []string, action func(command *CommandParsed) error) int {
+	command, err := registry.Parse(values)
+	if err == nil {
+		if command.Plugin != "" {
+			// the plugin writes its own error message
+			if err = registry.RunPlugin(command); err != nil {
+				if exitErr, ok := err.(*exec.ExitError); ok {
+					err = ErrorExit{Code: exitErr.ExitCode()}
+				}
+			}
+		} else if action != nil {
+			err = action(command)
+		}
+	}
+
+	commandName := ""
+	if command != nil {
+		commandName = command.Name
+	} else if name, _, resolveErr := registry.resolveCommand(values); resolveErr == nil {
+		commandName = name
+	}
+
+	return registry.handleError(err, commandName)
+}
+
+// Main method runs the command-line arguments of the program (see `Registry.Run`) and exits with the exit code.
+func (registry *Registry) Main(action func(command *CommandParsed) error) {
+	os.Exit(registry.Run(os.Args[1:], action))
+}
@@ -0,0 +1,107 @@
+package clapper_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/msaf1980/clapper"
+)
+
+// test the exit codes of the errors
+func TestExitCode(t *testing.T) {
+	cause := errors.New("cause")
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{nil, clapper.ExitSuccess},
+		{clapper.ErrorHelp{}, clapper.ExitSuccess},
+		{clapper.ErrorDumpArgs{}, clapper.ExitSuccess},
+		{clapper.ErrorUnknownFlag{Name: "--x"}, clapper.ExitUsage},
+		{clapper.ErrorMissingArg{Name: "x"}, clapper.ExitUsage},
+		{clapper.ErrorFlagValue{Name: "x", Value: "y", Err: cause}, clapper.ExitUsage},
+		{clapper.ErrorResponseFileCycle{File: "x"}, clapper.ExitDataErr},
+		{clapper.ErrorResponseFile{File: "x", Err: cause}, clapper.ExitNoInput},
+		{clapper.ErrorConfigFile{File: "x", Line: 1}, clapper.ExitConfig},
+		{clapper.ErrorExit{Code: 3}, 3},
+		{clapper.ErrorExit{Code: -1, Err: cause}, clapper.ExitFailure},
+		{clapper.ErrorExit{Code: 4, Err: clapper.ErrorMissingArg{Name: "x"}}, 4},
+		{fmt.Errorf("run: %w", clapper.ErrorUnknownCommand{Name: "x"}), clapper.ExitUsage},
+		{cause, clapper.ExitFailure},
+	}
+
+	for _, tt := range tests {
+		if got := clapper.ExitCode(tt.err); got != tt.code {
+			t.Errorf("%v: got exit code %d, want %d", tt.err, got, tt.code)
+		}
+	}
+
+	if runtime.GOOS != "windows" {
+		err := exec.Command("sh", "-c", "exit 5").Run()
+		if got := clapper.ExitCode(err); got != 5 {
+			t.Errorf("got exit code %d, want 5", got)
+		}
+	}
+}
+
+// test running the command-line arguments with the help flags
+func TestRun(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	registry := newDemoRegistry(true)
+	registry.HelpFlags = true
+	registry.Stdout = &stdout
+	registry.Stderr = &stderr
+
+	called := ""
+	action := func(command *clapper.CommandParsed) error {
+		called = command.Name
+		if command.Name == "ghost" {
+			return clapper.ErrorExit{Code: 2, Err: errors.New("no ghosts")}
+		}
+		return nil
+	}
+
+	// the help of the command
+	if code := registry.Run([]string{"info", "--help"}, action); code != clapper.ExitSuccess || called != "" {
+		t.Errorf("got exit code %d, called %q", code, called)
+	}
+	if help := stdout.String(); !strings.HasPrefix(help, "Usage: demo info [flags]") ||
+		!strings.Contains(help, "  -h, --help ") {
+		t.Errorf("got help %q", help)
+	}
+
+	// the action
+	if code := registry.Run([]string{"info", "student"}, action); code != clapper.ExitSuccess || called != "info" {
+		t.Errorf("got exit code %d, called %q", code, called)
+	}
+	if code := registry.Run([]string{"ghost"}, action); code != 2 {
+		t.Errorf("got exit code %d, want 2", code)
+	}
+	if want := "demo: no ghosts\n"; stderr.String() != want {
+		t.Errorf("got stderr %q, want %q", stderr.String(), want)
+	}
+
+	// a usage error
+	stderr.Reset()
+	if code := registry.Run([]string{"info", "--unknown"}, action); code != clapper.ExitUsage {
+		t.Errorf("got exit code %d, want %d", code, clapper.ExitUsage)
+	}
+	want := "demo: unknown flag --unknown found in the arguments\n" +
+		"Usage: demo info [flags] [category] [username] [subjects...]\n" +
+		"Run 'demo info --help' for more information.\n"
+	if stderr.String() != want {
+		t.Errorf("got stderr %q, want %q", stderr.String(), want)
+	}
+
+	// the help flag registered by the command
+	registry.Commands["info"].AddFlag("host", "h", false, "")
+	stdout.Reset()
+	if code := registry.Run([]string{"info", "-h", "local"}, action); code != clapper.ExitSuccess || stdout.Len() != 0 {
+		t.Errorf("got exit code %d, stdout %q", code, stdout.String())
+	}
+}
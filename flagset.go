@@ -0,0 +1,228 @@
+package clapper
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// ErrorFlagValue represents an error when the bound value of a flag (see `FlagCommand.SetFlagValue`) rejects a value.
+// The `Type` field is the type name of a `Value` (empty for other values).
+// The value and the message of the error of a sensitive flag are replaced with `RedactedValue`,
+// since the error of the bound value can contain the value.
+type ErrorFlagValue struct {
+	Name      string
+	Type      string
+	Value     string
+	Err       error
+	Sensitive bool
+}
+
+func (e ErrorFlagValue) Error() string {
+	if e.Type != "" {
+		return fmt.Sprintf("invalid %s value %s=%s found in the arguments: %v", e.Type, e.Name, e.Value, e.cause())
+	}
+	return fmt.Sprintf("invalid value %s=%s found in the arguments: %v", e.Name, e.Value, e.cause())
+}
+
+// return the error of the bound value or `RedactedValue` for a sensitive flag
+func (e ErrorFlagValue) cause() interface{} {
+	if e.Sensitive {
+		return RedactedValue
+	}
+	return e.Err
+}
+
+// Unwrap method returns the error of the bound value.
+func (e ErrorFlagValue) Unwrap() error {
+	return e.Err
+}
+
+// ErrorArgValue represents an error when the bound value of an argument (see `ArgCommand.SetArgValue`) rejects a value.
+// The `Type` field is the type name of a `Value` (empty for other values).
+type ErrorArgValue struct {
+	Name  string
+	Type  string
+	Value string
+	Err   error
+}
+
+func (e ErrorArgValue) Error() string {
+	if e.Type != "" {
+		return fmt.Sprintf("invalid %s argument %s=%s found in the arguments: %v", e.Type, e.Name, e.Value, e.Err)
+	}
+	return fmt.Sprintf("invalid argument %s=%s found in the arguments: %v", e.Name, e.Value, e.Err)
+}
+
+// Unwrap method returns the error of the bound value.
+func (e ErrorArgValue) Unwrap() error {
+	return e.Err
+}
+
+/*---------------------*/
+
+// check if a `flag.Value` is a boolean flag value (see `flag.FlagSet.Var`)
+func isBoolValue(value flag.Value) bool {
+	b, ok := value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// SetFlagValue binds the flag to a `flag.Value`. After a successful parse, the value of the flag
+// (unless it is the default value) is set with `flag.Value.Set` ("false" for `--no-<flag>` of an inverted flag).
+// The parsed value of a `Value` is replaced with its `Value.String` result.
+// The value is validated before any bound value is set if the bound value implements `Validator`.
+// Parsing of a registry with bound flags is not safe for concurrent use.
+func (f *FlagCommand) SetFlagValue(value flag.Value) *FlagCommand {
+	f.FlagValue = value
+	return f
+}
+
+// ImportFlagSet registers the flags of a standard `flag.FlagSet` (for example `flag.CommandLine`) with the command.
+// Flags keep their names (a one-letter name is also a short name), defaults and usage, and they are bound
+// to the `flag.Value` of the flag set (see `FlagCommand.SetFlagValue`), so the variables of the flag set
+// are set by `Registry.Parse`. A boolean flag named `no-<flag>` is an inverted flag. Like with the `flag` package,
+// a boolean flag accepts `--<flag>=<bool>` (for example `--color=false`) and it is turned off by `--no-<flag>`.
+// Flags with the names registered for the command are skipped.
+func (commandConfig *CommandConfig) ImportFlagSet(fs *flag.FlagSet) *CommandConfig {
+	fs.VisitAll(func(f *flag.Flag) {
+		shortName := ""
+		if len(f.Name) == 1 {
+			shortName = f.Name
+		}
+
+		isBool := isBoolValue(f.Value)
+		flag, exist := commandConfig.AddFlag(f.Name, shortName, isBool, f.DefValue)
+		if exist {
+			return
+		}
+
+		flag.SetDescription(f.Usage).SetFlagValue(f.Value)
+		if isBool && !flag.IsInverted {
+			if b, err := strconv.ParseBool(f.DefValue); err == nil {
+				flag.DefaultValue = strconv.FormatBool(b)
+			}
+		}
+	})
+
+	return commandConfig
+}
+
+// FlagSet returns a new standard `flag.FlagSet` with the flags of the command, for example to pass them
+// to a package expecting a `flag.FlagSet`. Bound flags use their `flag.Value` (see `FlagCommand.SetFlagValue`),
+// other flags are string or boolean flags with the default values. Short names are registered as separate
+// flags sharing the value of the long name and inverted flags are registered as `no-<flag>` boolean flags.
+func (commandConfig *CommandConfig) FlagSet(name string, errorHandling flag.ErrorHandling) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, errorHandling)
+
+	names := make([]string, 0, len(commandConfig.Flags))
+	for flagName := range commandConfig.Flags {
+		names = append(names, flagName)
+	}
+	sort.Strings(names)
+
+	for _, flagName := range names {
+		f := commandConfig.Flags[flagName]
+
+		longName := f.Name
+		if f.IsInverted {
+			longName = "no-" + f.Name
+		}
+
+		value := f.FlagValue
+		if value == nil {
+			if f.IsBoolean {
+				b, _ := strconv.ParseBool(f.DefaultValue)
+				if f.IsInverted {
+					b = !b
+				}
+				value = (*boolValue)(&b)
+			} else {
+				s := f.DefaultValue
+				value = (*stringValue)(&s)
+			}
+		}
+
+		fs.Var(value, longName, f.Description)
+		if f.ShortName != "" && fs.Lookup(f.ShortName) == nil {
+			fs.Var(value, f.ShortName, "shorthand for -"+longName)
+		}
+	}
+
+	return fs
+}
+
+// return the parsed values of the bound flags
+func (commandConfig *CommandConfig) flagBindings(store *CommandParsed) []binding {
+	bindings := make([]binding, 0)
+	for _, name := range commandConfig.FlagNames() {
+		f := commandConfig.Flags[name]
+		parsed, ok := store.Flags[name]
+		if f.FlagValue == nil || !ok || !parsed.IsSet() {
+			continue
+		}
+
+		value := parsed.Value
+		if f.IsInverted {
+			b, _ := strconv.ParseBool(value)
+			value = strconv.FormatBool(!b)
+		}
+		b := binding{
+			target: f.FlagValue,
+			value:  value,
+			fail: func(err error) error {
+				return ErrorFlagValue{f.Name, f.Type(), f.redact(value), err, f.Sensitive}
+			},
+		}
+
+		// the value of a custom type is normalized by the type
+		if typed, ok := f.FlagValue.(Value); ok && !f.IsBoolean {
+			b.done = func() {
+				parsed.Value = typed.String()
+			}
+		}
+		bindings = append(bindings, b)
+	}
+
+	return bindings
+}
+
+/*---------------------*/
+
+// `flag.Value` of a string flag of an exported flag set
+type stringValue string
+
+func (v *stringValue) Set(s string) error {
+	*v = stringValue(s)
+	return nil
+}
+
+func (v *stringValue) String() string {
+	if v == nil {
+		return ""
+	}
+	return string(*v)
+}
+
+// `flag.Value` of a boolean flag of an exported flag set
+type boolValue bool
+
+func (v *boolValue) Set(s string) error {
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	*v = boolValue(b)
+	return nil
+}
+
+func (v *boolValue) String() string {
+	if v == nil {
+		return "false"
+	}
+	return strconv.FormatBool(bool(*v))
+}
+
+func (v *boolValue) IsBoolFlag() bool {
+	return true
+}
@@ -0,0 +1,116 @@
+package clapper_test
+
+import (
+	"errors"
+	"flag"
+	"io/ioutil"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/msaf1980/clapper"
+	"github.com/msaf1980/clapper/clappertest"
+)
+
+// test import of a standard flag set
+func TestImportFlagSet(t *testing.T) {
+	fs := flag.NewFlagSet("legacy", flag.ContinueOnError)
+	timeout := fs.Duration("timeout", time.Second, "request timeout")
+	verbose := fs.Bool("v", false, "verbose output")
+	color := fs.Bool("color", true, "colored output")
+	noCache := fs.Bool("no-cache", false, "disable the cache")
+	name := fs.String("name", "guest", "user name")
+
+	registry := clapper.NewRegistry()
+	rootCommand, _ := registry.Register("")
+	rootCommand.AddFlag("name", "", false, "root")
+	rootCommand.ImportFlagSet(fs)
+
+	timeoutFlag := rootCommand.Flags["timeout"]
+	if timeoutFlag.DefaultValue != "1s" || timeoutFlag.Description != "request timeout" || timeoutFlag.IsBoolean {
+		t.Errorf("got %#v", timeoutFlag)
+	}
+	if f := rootCommand.Flags["v"]; f.ShortName != "v" || !f.IsBoolean {
+		t.Errorf("got %#v", f)
+	}
+	if f := rootCommand.Flags["cache"]; !f.IsInverted || f.DefaultValue != "true" {
+		t.Errorf("got %#v", f)
+	}
+	if f := rootCommand.Flags["name"]; f.DefaultValue != "root" || f.FlagValue != nil {
+		t.Errorf("registered flag is replaced: %#v", f)
+	}
+
+	command := clappertest.Parse(t, registry, "--timeout", "5m", "-v", "--no-cache", "--name", "me")
+	clappertest.AssertFlags(t, command, map[string]string{"timeout": "5m", "v": "true", "cache": "false", "color": "true"})
+	if *timeout != 5*time.Minute || !*verbose || !*noCache || !*color || *name != "guest" {
+		t.Errorf("got timeout=%v, v=%t, no-cache=%t, color=%t, name=%q", *timeout, *verbose, *noCache, *color, *name)
+	}
+
+	// the boolean flags are turned off with a value or the `no-` prefix
+	for _, args := range [][]string{{"--color=false"}, {"--no-color"}, {"--color=0", "--v=true", "--no-cache=false"}} {
+		command = clappertest.Parse(t, registry, args...)
+		clappertest.AssertFlags(t, command, map[string]string{"color": "false"})
+		if *color || len(command.Passthrough) != 0 {
+			t.Errorf("%q: got color=%t, passthrough %q", args, *color, command.Passthrough)
+		}
+	}
+	clappertest.AssertFlags(t, command, map[string]string{"v": "true", "cache": "true"})
+
+	result := clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"--color=maybe"}})
+	clappertest.AssertError(t, result.Err, clapper.ErrorUnsupportedValue{Name: "color", Value: "maybe"})
+
+	result = clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"--timeout", "soon"}})
+	var valueErr clapper.ErrorFlagValue
+	if !errors.As(result.Err, &valueErr) || valueErr.Name != "timeout" || valueErr.Value != "soon" || valueErr.Err == nil {
+		t.Errorf("got %#v", result.Err)
+	}
+}
+
+// test export of a command to a standard flag set
+func TestFlagSet(t *testing.T) {
+	var limit int
+	registry := newDemoRegistry(false)
+	infoCommand := registry.Commands["info"]
+	infoCommand.Flags["output"].SetDescription("output directory")
+	limitFlag, _ := infoCommand.AddFlag("limit", "l", false, "10")
+	limitFlag.SetFlagValue(intValue{&limit})
+
+	fs := infoCommand.FlagSet("info", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	if err := fs.Parse([]string{"-o", "/tmp", "-verbose", "-no-clean", "-limit", "5"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"output": "/tmp", "o": "/tmp", "verbose": "true", "v": "true", "no-clean": "true", "version": "1.0.1", "limit": "5"}
+	for name, value := range want {
+		if f := fs.Lookup(name); f == nil || f.Value.String() != value {
+			t.Errorf("flag(%s) is %#v, want %q", name, f, value)
+		}
+	}
+	if f := fs.Lookup("output"); f.Usage != "output directory" || f.DefValue != "./" {
+		t.Errorf("got %#v", f)
+	}
+	if limit != 5 {
+		t.Errorf("got limit %d", limit)
+	}
+}
+
+// `flag.Value` of an integer
+type intValue struct {
+	p *int
+}
+
+func (v intValue) Set(s string) error {
+	i, err := strconv.Atoi(s)
+	if err == nil {
+		*v.p = i
+	}
+	return err
+}
+
+func (v intValue) String() string {
+	if v.p == nil {
+		return "0"
+	}
+	return strconv.Itoa(*v.p)
+}
@@ -0,0 +1,231 @@
+package clapper
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// return the name of the program
+func (registry *Registry) programName() string {
+	if registry.Name != "" {
+		return registry.Name
+	}
+	return filepath.Base(os.Args[0])
+}
+
+// format the usage line of an argument
+func argUsage(arg *ArgCommand) string {
+	name := arg.Name
+	if arg.IsVariadic {
+		name += "..."
+	}
+	if arg.Required {
+		return "<" + name + ">"
+	}
+	return "[" + name + "]"
+}
+
+// format the names of a flag
+func flagUsage(flag *FlagCommand, l localizer) string {
+	var names string
+	if flag.ShortName != "" {
+		names = "-" + flag.ShortName + ", "
+	} else {
+		names = "    "
+	}
+
+	if flag.IsInverted {
+		names += "--no-" + flag.Name
+	} else {
+		names += "--" + flag.Name
+	}
+
+	if typeName := flag.Type(); !flag.IsBoolean && typeName != "" {
+		names += " <" + typeName + ">"
+	} else if !flag.IsBoolean {
+		names += " " + l.format("help.value")
+	}
+
+	return names
+}
+
+// format the description of a flag or an argument with the default and valid values
+func (l localizer) describe(description string, required bool, defaultValue string, validVals []string) string {
+	parts := make([]string, 0, 4)
+	if description != "" {
+		parts = append(parts, l.text(description))
+	}
+	if required {
+		parts = append(parts, l.format("help.required"))
+	}
+	if defaultValue != "" {
+		parts = append(parts, l.format("help.default", defaultValue))
+	}
+	if len(validVals) > 0 {
+		vals := make([]string, len(validVals))
+		for i, v := range validVals {
+			if v == "" {
+				v = `""`
+			}
+			vals[i] = v
+		}
+		parts = append(parts, fmt.Sprintf("[%s]", strings.Join(vals, ", ")))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// return the names of the commands shown in the help
+func (registry *Registry) visibleCommandNames() []string {
+	names := make([]string, 0, len(registry.Commands))
+	for _, name := range registry.CommandNames() {
+		if name == "" || !registry.Commands[name].isHidden() {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// return the names of the flags shown in the help
+func (commandConfig *CommandConfig) visibleFlagNames() []string {
+	names := make([]string, 0, len(commandConfig.Flags))
+	for _, name := range commandConfig.FlagNames() {
+		if !commandConfig.Flags[name].isHidden() {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// WriteHelp method writes the help text of the command registered with the name to the writer,
+// colored and wrapped for a terminal (see `Registry.Color` and `Registry.Width`).
+// If command is not registered, it return `ErrorUnknownCommand` error.
+func (registry *Registry) WriteHelp(w io.Writer, name string) error {
+	help, err := registry.help(name, registry.output(w))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, help)
+	return err
+}
+
+// Help method returns the help text of the command registered with the name
+// in the language of the registry (see `Registry.Locale` and `Registry.Catalog`).
+// The text is colored only with `ColorAlways` mode (see `Registry.Color`).
+// If command is not registered, it return `ErrorUnknownCommand` error.
+func (registry *Registry) Help(name string) (string, error) {
+	return registry.help(name, registry.output(nil))
+}
+
+// return the help text of the command formatted for the output
+func (registry *Registry) help(name string, o output) (string, error) {
+	commandConfig, ok := registry.Commands[removeWhitespaces(name)]
+	if !ok {
+		return "", ErrorUnknownCommand{name}
+	}
+
+	var w bytes.Buffer
+	l := registry.messages()
+
+	// usage line
+	usage := []string{l.format("help.usage"), o.paint(o.theme.Command, registry.programName())}
+	if commandConfig.Name != "" {
+		usage = append(usage, o.paint(o.theme.Command, commandConfig.Name))
+	}
+	plugins := make([]string, 0)
+	if commandConfig.Name == "" {
+		plugins = registry.PluginNames()
+	}
+	if commandConfig.Name == "" && (len(registry.visibleCommandNames()) > 1 || len(plugins) > 0) {
+		usage = append(usage, l.format("help.command"))
+	}
+	helpFlag, versionFlag := registry.helpFlagUsage(commandConfig), versionFlagUsage(commandConfig)
+	if len(commandConfig.visibleFlagNames()) > 0 || helpFlag != "" || versionFlag != "" {
+		usage = append(usage, l.format("help.flags"))
+	}
+	for _, argName := range commandConfig.ArgNames {
+		usage = append(usage, argUsage(commandConfig.Args[argName]))
+	}
+	fmt.Fprintln(&w, strings.Join(usage, " "))
+
+	if commandConfig.Description != "" {
+		fmt.Fprintf(&w, "\n%s\n", o.wrap(l.text(commandConfig.Description), 0))
+	}
+
+	if len(commandConfig.Aliases) > 0 {
+		fmt.Fprintf(&w, "\n%s\n", l.format("help.aliases", strings.Join(commandConfig.Aliases, ", ")))
+	}
+
+	// sub-commands (for the root command)
+	if commandConfig.Name == "" && (len(registry.visibleCommandNames()) > 1 || len(plugins) > 0) {
+		fmt.Fprintf(&w, "\n%s\n", o.paint(o.theme.Heading, l.format("help.commandsHeading")))
+		rows := make([]row, 0)
+		for _, commandName := range registry.visibleCommandNames() {
+			if commandName != "" {
+				rows = append(rows, row{"  " + commandName, o.theme.Command, l.text(registry.Commands[commandName].Description)})
+			}
+		}
+		for _, pluginName := range plugins {
+			rows = append(rows, row{"  " + pluginName, o.theme.Command, l.format("help.plugin", registry.programName()+"-"+pluginName)})
+		}
+		o.writeTable(&w, rows)
+	}
+
+	if len(commandConfig.ArgNames) > 0 {
+		fmt.Fprintf(&w, "\n%s\n", o.paint(o.theme.Heading, l.format("help.argumentsHeading")))
+		rows := make([]row, 0, len(commandConfig.ArgNames))
+		for _, argName := range commandConfig.ArgNames {
+			arg := commandConfig.Args[argName]
+			name := arg.Name
+			if commandConfig.NamedArgs {
+				name += ", --" + arg.Name
+			}
+			rows = append(rows, row{"  " + name, o.theme.Arg, l.describe(arg.Description, arg.Required, arg.DefaultValue, arg.ValidValsList())})
+		}
+		o.writeTable(&w, rows)
+	}
+
+	if len(commandConfig.visibleFlagNames()) > 0 || helpFlag != "" || versionFlag != "" {
+		fmt.Fprintf(&w, "\n%s\n", o.paint(o.theme.Heading, l.format("help.flagsHeading")))
+		rows := make([]row, 0, len(commandConfig.Flags)+2)
+		for _, flagName := range commandConfig.visibleFlagNames() {
+			flag := commandConfig.Flags[flagName]
+			defaultValue := flag.redact(flag.DefaultValue)
+			validVals := flag.ValidValsList()
+			if flag.IsBoolean {
+				defaultValue = "" // default value of a boolean flag is implied
+			}
+			if flag.Sensitive {
+				validVals = nil
+			}
+			description := l.describe(flag.Description, flag.Required, defaultValue, validVals)
+			if flag.EnvVar != "" {
+				description = strings.TrimLeft(description+" "+l.format("help.env", flag.EnvVar), " ")
+			}
+			rows = append(rows, row{"  " + flagUsage(flag, l), o.theme.Flag, description})
+			if flag.sensitiveFile() {
+				rows = append(rows, sensitiveFileRow(flag, o, l))
+			}
+		}
+		if helpFlag != "" {
+			rows = append(rows, row{"  " + helpFlag, o.theme.Flag, l.format("help.helpFlag")})
+		}
+		if versionFlag != "" {
+			rows = append(rows, row{"  " + versionFlag, o.theme.Flag, l.format("help.versionFlag")})
+		}
+		o.writeTable(&w, rows)
+	}
+
+	// remove the spaces at the end of lines
+	lines := strings.Split(w.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
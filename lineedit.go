@@ -0,0 +1,192 @@
+package clapper
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// errInterrupt is returned by the line editor when the line is cancelled with Ctrl-C
+var errInterrupt = errors.New("interrupted")
+
+// control keys of the line editor
+const (
+	keyInterrupt = 3   // Ctrl-C
+	keyEOF       = 4   // Ctrl-D
+	keyBackspace = 8   // Ctrl-H
+	keyTab       = 9   // Tab
+	keyNewline   = 10  // Ctrl-J
+	keyReturn    = 13  // Enter
+	keyKill      = 21  // Ctrl-U
+	keyEscape    = 27  // start of an escape sequence
+	keyDelete    = 127 // Backspace
+)
+
+// line editor reading from a terminal in raw mode
+// the line is edited at the end: typed characters are appended, Backspace removes the last character,
+// Up and Down keys recall the history, Tab completes the last word
+type lineEditor struct {
+	in  *bufio.Reader
+	out io.Writer
+
+	// prompt written before the line
+	prompt string
+
+	// previous lines (the oldest first)
+	history []string
+
+	// function returning the candidates completing the last value (see `Registry.Complete`)
+	complete func(values []string, toComplete string) []string
+}
+
+// redraw the prompt and the line
+func (e *lineEditor) redraw(line []rune) {
+	fmt.Fprintf(e.out, "\r\x1b[K%s%s", e.prompt, string(line))
+}
+
+// read a line, returns `io.EOF` error on Ctrl-D at the beginning of the line and `errInterrupt` error on Ctrl-C
+func (e *lineEditor) readLine() (string, error) {
+	line := make([]rune, 0)
+	position := len(e.history) // position in the history (`len(e.history)` is the edited line)
+	edited := ""               // the edited line while the history is browsed
+
+	e.redraw(line)
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			if err == io.EOF && len(line) > 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return string(line), nil
+			}
+			return "", err
+		}
+
+		switch r {
+		case keyReturn, keyNewline:
+			fmt.Fprint(e.out, "\r\n")
+			return string(line), nil
+		case keyInterrupt:
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", errInterrupt
+		case keyEOF:
+			if len(line) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+		case keyDelete, keyBackspace:
+			if len(line) > 0 {
+				line = line[:len(line)-1]
+				e.redraw(line)
+			}
+		case keyKill:
+			line = line[:0]
+			e.redraw(line)
+		case keyTab:
+			line = e.completeLine(line)
+			e.redraw(line)
+		case keyEscape:
+			switch e.readEscape() {
+			case 'A': // Up
+				if position > 0 {
+					if position == len(e.history) {
+						edited = string(line)
+					}
+					position--
+					line = []rune(e.history[position])
+					e.redraw(line)
+				}
+			case 'B': // Down
+				if position < len(e.history) {
+					position++
+					if position == len(e.history) {
+						line = []rune(edited)
+					} else {
+						line = []rune(e.history[position])
+					}
+					e.redraw(line)
+				}
+			}
+		default:
+			if r >= ' ' {
+				line = append(line, r)
+				fmt.Fprint(e.out, string(r))
+			}
+		}
+	}
+}
+
+// read an escape sequence (`ESC [ ... <final>` or `ESC O <final>`), returns its final character
+func (e *lineEditor) readEscape() rune {
+	r, _, err := e.in.ReadRune()
+	if err != nil || (r != '[' && r != 'O') {
+		return 0
+	}
+
+	for {
+		r, _, err = e.in.ReadRune()
+		if err != nil {
+			return 0
+		}
+		if r >= 0x40 && r <= 0x7e {
+			return r
+		}
+	}
+}
+
+// complete the last word of the line
+// a single candidate replaces the word, multiple candidates are listed unless they extend the word with a common prefix
+func (e *lineEditor) completeLine(line []rune) []rune {
+	if e.complete == nil {
+		return line
+	}
+
+	tokens, err := tokenize(string(line))
+	if err != nil {
+		return line
+	}
+
+	// check if the last word is being typed (a character appended to the line would be a part of it)
+	values := make([]string, len(tokens))
+	for i, t := range tokens {
+		values[i] = t.value
+	}
+	start := len(line)
+	toComplete := ""
+	if extended, err := tokenize(string(line) + "x"); err == nil && len(extended) == len(tokens) && len(tokens) > 0 {
+		last := tokens[len(tokens)-1]
+		start = last.column - 1
+		toComplete = last.value
+		values = values[:len(values)-1]
+	}
+
+	candidates := e.complete(values, toComplete)
+	switch {
+	case len(candidates) == 0:
+		return line
+	case len(candidates) == 1:
+		return append(line[:start:start], []rune(Quote(candidates[0])+" ")...)
+	}
+
+	if prefix := commonPrefix(candidates); len(prefix) > len(toComplete) {
+		return append(line[:start:start], []rune(Quote(prefix))...)
+	}
+
+	fmt.Fprintf(e.out, "\r\n%s\r\n", strings.Join(candidates, "  "))
+	return line
+}
+
+// return the common prefix of the values
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, value := range values[1:] {
+		for !strings.HasPrefix(value, prefix) {
+			_, size := utf8.DecodeLastRuneInString(prefix)
+			prefix = prefix[:len(prefix)-size]
+		}
+	}
+
+	return prefix
+}
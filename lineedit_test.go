@@ -0,0 +1,59 @@
+package clapper
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// test editing of the lines
+func TestLineEditor(t *testing.T) {
+	registry := NewRegistry()
+	infoCommand, _ := registry.Register("info")
+	infoCommand.AddArgWithValid("category", "", []string{"manager", "my dir"})
+	infoCommand.AddFlag("output", "o", false, "")
+	infoCommand.AddFlag("offline", "", true, "")
+	registry.Register("ghost")
+
+	var out bytes.Buffer
+	editor := &lineEditor{
+		out:      &out,
+		prompt:   "> ",
+		history:  []string{"ghost", "info manager"},
+		complete: registry.Complete,
+	}
+
+	tests := []struct {
+		input string
+		want  string
+		err   error
+	}{
+		{"in\tma\t\n", "info manager ", nil},
+		{"info my\t\n", "info 'my dir' ", nil},
+		{"info --o\tf\t\n", "info --offline ", nil},
+		{"ghosx\x7ft\n", "ghost", nil},
+		{"abc\x15ghost\r", "ghost", nil},
+		{"x\x1b[A\x1b[A\x1b[B\n", "info manager", nil},
+		{"\x1b[A\x1b[B\x1b[B\n", "", nil},
+		{"abc\x03", "", errInterrupt},
+		{"\x04", "", io.EOF},
+		{"info", "info", nil},
+	}
+
+	for _, tt := range tests {
+		editor.in = bufio.NewReader(strings.NewReader(tt.input))
+		line, err := editor.readLine()
+		if line != tt.want || err != tt.err {
+			t.Errorf("%q: got %q (%v), want %q (%v)", tt.input, line, err, tt.want, tt.err)
+		}
+	}
+
+	// multiple candidates are listed
+	out.Reset()
+	editor.in = bufio.NewReader(strings.NewReader("info --o\t\n"))
+	if line, _ := editor.readLine(); line != "info --o" || !strings.Contains(out.String(), "\r\n--offline  --output\r\n") {
+		t.Errorf("got %q, output %q", line, out.String())
+	}
+}
@@ -0,0 +1,93 @@
+package clapper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Occurrence type holds a flag or an argument value found in the command-line arguments.
+// Values from the environment, configurations, prompts and defaults have no occurrences.
+type Occurrence struct {
+	// long name of the flag or name of the argument
+	Name string
+
+	// if the occurrence is a flag
+	IsFlag bool
+
+	// if the value is sensitive (see `FlagCommand.SetSensitive`)
+	Sensitive bool
+
+	// value of the occurrence (`true` or `false` for a boolean flag)
+	Value string
+
+	// index of the flag or argument in the values passed to `Registry.Parse` (after the response file expansion)
+	Index int
+
+	// command-line argument at the index, for example `-o`, `--output=./` or `--no-clean`
+	Raw string
+}
+
+// String method returns the raw command-line argument of the occurrence (redacted if the value is sensitive).
+func (occurrence Occurrence) String() string {
+	if occurrence.Sensitive && strings.Contains(occurrence.Raw, "=") {
+		return occurrence.Raw[:strings.Index(occurrence.Raw, "=")+1] + RedactedValue
+	}
+	return occurrence.Raw
+}
+
+// GoString method returns the Go syntax representation of the occurrence (used by `%#v`) with a redacted sensitive value.
+func (occurrence Occurrence) GoString() string {
+	value := occurrence.Value
+	if occurrence.Sensitive && value != "" {
+		value = RedactedValue
+	}
+	return fmt.Sprintf("clapper.Occurrence{Name:%q, IsFlag:%t, Sensitive:%t, Value:%q, Index:%d, Raw:%q}",
+		occurrence.Name, occurrence.IsFlag, occurrence.Sensitive, value, occurrence.Index, occurrence.String())
+}
+
+// append a flag occurrence
+func (commandParsed *CommandParsed) addFlagOccurrence(flag *FlagCommand, value string, index int, values []string) {
+	commandParsed.Occurrences = append(commandParsed.Occurrences, &Occurrence{
+		Name:      flag.Name,
+		IsFlag:    true,
+		Sensitive: flag.Sensitive,
+		Value:     value,
+		Index:     index,
+		Raw:       values[index],
+	})
+}
+
+// append an argument occurrence
+func (commandParsed *CommandParsed) addArgOccurrence(name string, value string, index int, values []string) {
+	commandParsed.Occurrences = append(commandParsed.Occurrences, &Occurrence{
+		Name:  name,
+		Value: value,
+		Index: index,
+		Raw:   values[index],
+	})
+}
+
+// FlagValues method returns the values of all occurrences of the flag in the command-line arguments order,
+// for example `[a b]` for `--include=a --include=b`. `Flags` holds only the last value.
+func (commandParsed *CommandParsed) FlagValues(name string) []string {
+	values := make([]string, 0)
+	for _, occurrence := range commandParsed.Occurrences {
+		if occurrence.IsFlag && occurrence.Name == name {
+			values = append(values, occurrence.Value)
+		}
+	}
+
+	return values
+}
+
+// FlagOccurrences method returns the flag occurrences in the command-line arguments order.
+func (commandParsed *CommandParsed) FlagOccurrences() []*Occurrence {
+	occurrences := make([]*Occurrence, 0, len(commandParsed.Occurrences))
+	for _, occurrence := range commandParsed.Occurrences {
+		if occurrence.IsFlag {
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+
+	return occurrences
+}
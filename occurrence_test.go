@@ -0,0 +1,83 @@
+package clapper_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/msaf1980/clapper"
+	"github.com/msaf1980/clapper/clappertest"
+)
+
+// test ordered occurrences of the flags and arguments
+func TestOccurrences(t *testing.T) {
+	registry := newDemoRegistry(false)
+	flag, _ := registry.Commands["info"].AddFlag("token", "", false, "")
+	flag.SetSensitive(true)
+
+	command := clappertest.Parse(t, registry,
+		"info", "--output=/tmp", "student", "-V", "2.0.0", "-v", "--output", "/var", "--token=s3cr3t", "--", "jane", "math")
+
+	want := []clapper.Occurrence{
+		{Name: "output", IsFlag: true, Value: "/tmp", Index: 1, Raw: "--output=/tmp"},
+		{Name: "category", Value: "student", Index: 2, Raw: "student"},
+		{Name: "version", IsFlag: true, Value: "2.0.0", Index: 3, Raw: "-V"},
+		{Name: "verbose", IsFlag: true, Value: "true", Index: 5, Raw: "-v"},
+		{Name: "output", IsFlag: true, Value: "/var", Index: 6, Raw: "--output"},
+		{Name: "token", IsFlag: true, Sensitive: true, Value: "s3cr3t", Index: 8, Raw: "--token=s3cr3t"},
+		{Name: "username", Value: "jane", Index: 10, Raw: "jane"},
+		{Name: "subjects", Value: "math", Index: 11, Raw: "math"},
+	}
+	got := make([]clapper.Occurrence, 0, len(command.Occurrences))
+	for _, occurrence := range command.Occurrences {
+		got = append(got, *occurrence)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got\n%#v\nwant\n%#v", got, want)
+	}
+
+	if got, want := command.FlagValues("output"), []string{"/tmp", "/var"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	clappertest.AssertFlags(t, command, map[string]string{"output": "/var"})
+	if got := len(command.FlagOccurrences()); got != 5 {
+		t.Errorf("got %d flag occurrences", got)
+	}
+
+	if got, want := command.ArgNames, []string{"category", "username", "subjects"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := command.String(), `info category="student" username="jane" subjects="math" --clean="true" --output="/var" --token="<redacted>" --verbose="true" --version="2.0.0"`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	token := command.Occurrences[5]
+	for _, formatted := range []string{token.String(), fmt.Sprintf("%#v", token), fmt.Sprintf("%#v", *token)} {
+		if strings.Contains(formatted, "s3cr3t") {
+			t.Errorf("sensitive value is not redacted in %q", formatted)
+		}
+	}
+}
+
+// test occurrences of a sub-command and values from other sources
+func TestOccurrencesCommand(t *testing.T) {
+	registry := newDemoRegistry(false)
+	registry.Commands["info"].Flags["output"].SetEnvVar("APP_OUTPUT")
+
+	result := clappertest.Run(t, registry, clappertest.Fixture{
+		Args: []string{"info"},
+		Env:  map[string]string{"APP_OUTPUT": "/tmp"},
+	})
+	if result.Err != nil {
+		t.Fatal(result.Err)
+	}
+	if len(result.Command.Occurrences) != 0 {
+		t.Errorf("got %#v", result.Command.Occurrences)
+	}
+
+	command := clappertest.Parse(t, registry, "info", "-o", "/var")
+	if got := command.Occurrences; len(got) != 1 || got[0].Index != 1 || got[0].Raw != "-o" {
+		t.Errorf("got %#v", got)
+	}
+}
@@ -0,0 +1,123 @@
+package clapper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Ordering type describes the accepted placement of the flags and arguments (see `Registry.Ordering`).
+type Ordering int
+
+const (
+	// OrderDefault uses the ordering of the registry (for a command) or `OrderPermute`
+	// (`OrderRequire` if the `POSIXLY_CORRECT` environment variable is set)
+	OrderDefault Ordering = iota
+
+	// OrderPermute accepts the flags and arguments in any order after the command name (GNU getopt(3) permutation),
+	// for example `info -v student --output ./ math`
+	OrderPermute
+
+	// OrderRequire stops the processing of the flags at the first argument (POSIX getopt(3)),
+	// all values after it are arguments, for example `info -v student --output` has the `--output` argument
+	OrderRequire
+
+	// OrderFlagsBeforeCommand accepts the flags only before the command name and all values after it are arguments,
+	// for example `-v --output ./ info student` (the command is the first registered command name
+	// which is not the value of a flag)
+	OrderFlagsBeforeCommand
+
+	// OrderFlagsAfterArgs accepts the flags only after the arguments, for example `info student math -v --output ./`,
+	// an argument after a flag is `ErrorMisplacedArg` error
+	OrderFlagsAfterArgs
+)
+
+// PosixlyCorrectEnv is the environment variable selecting `OrderRequire` ordering if the ordering is `OrderDefault`.
+const PosixlyCorrectEnv = "POSIXLY_CORRECT"
+
+// ErrorMisplacedArg represents an error when command-line arguments contain an argument after a flag
+// with `OrderFlagsAfterArgs` ordering.
+type ErrorMisplacedArg struct {
+	Value string
+}
+
+func (e ErrorMisplacedArg) Error() string {
+	return fmt.Sprintf("argument %s found after the flags in the arguments", e.Value)
+}
+
+// names of the orderings used in the schema (see `Ordering.MarshalText`)
+var orderingNames = []string{"default", "permute", "require", "flags-before-command", "flags-after-args"}
+
+// String method returns the name of the ordering, for example `permute`.
+func (ordering Ordering) String() string {
+	if ordering < OrderDefault || int(ordering) >= len(orderingNames) {
+		return fmt.Sprintf("Ordering(%d)", int(ordering))
+	}
+	return orderingNames[ordering]
+}
+
+// MarshalText method implements `encoding.TextMarshaler` interface, the ordering is serialized by name.
+func (ordering Ordering) MarshalText() ([]byte, error) {
+	if ordering < OrderDefault || int(ordering) >= len(orderingNames) {
+		return nil, fmt.Errorf("invalid ordering %d", int(ordering))
+	}
+	return []byte(orderingNames[ordering]), nil
+}
+
+// UnmarshalText method implements `encoding.TextUnmarshaler` interface.
+func (ordering *Ordering) UnmarshalText(text []byte) error {
+	for i, name := range orderingNames {
+		if name == string(text) {
+			*ordering = Ordering(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid ordering %q", text)
+}
+
+/*---------------------*/
+
+// SetOrdering sets the placement of the flags and arguments of the command (see `Ordering`).
+// `OrderFlagsBeforeCommand` ordering of a command makes all values after the command name arguments,
+// the flags before the command name are accepted only with `OrderFlagsBeforeCommand` ordering of the registry.
+func (commandConfig *CommandConfig) SetOrdering(ordering Ordering) *CommandConfig {
+	commandConfig.Ordering = ordering
+	return commandConfig
+}
+
+// return the ordering of the command (nil command for the ordering of the registry)
+func (registry *Registry) ordering(commandConfig *CommandConfig) Ordering {
+	if commandConfig != nil && commandConfig.Ordering != OrderDefault {
+		return commandConfig.Ordering
+	}
+	if registry.Ordering != OrderDefault {
+		return registry.Ordering
+	}
+	if _, ok := registry.lookupEnv(PosixlyCorrectEnv); ok {
+		return OrderRequire
+	}
+	return OrderPermute
+}
+
+// find the command name after the flags with `OrderFlagsBeforeCommand` ordering of the registry,
+// returns the index of the command name in the values or -1 for the root command
+// (a command name is skipped if it is the value of the preceding non-boolean flag of the command)
+func (registry *Registry) commandIndex(values []string) int {
+	for i, value := range values {
+		if isEndOfFlags(value) {
+			break
+		}
+		commandConfig, ok := registry.Commands[value]
+		if !ok || value == "" || isFlag(value) {
+			continue
+		}
+		if i > 0 {
+			previous := registry.canonicalFlag(commandConfig, values[i-1])
+			if flag := commandConfig.lookupFlagValue(previous); flag != nil && !flag.IsBoolean && !strings.Contains(previous, "=") {
+				continue
+			}
+		}
+		return i
+	}
+
+	return -1
+}
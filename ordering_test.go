@@ -0,0 +1,143 @@
+package clapper_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/msaf1980/clapper"
+	"github.com/msaf1980/clapper/clappertest"
+)
+
+// test the orderings of the flags and arguments
+func TestOrdering(t *testing.T) {
+	tests := []struct {
+		name     string
+		ordering clapper.Ordering
+		args     []string
+		command  string
+		flags    map[string]string
+		argVals  map[string]string
+	}{
+		{
+			name:     "permute",
+			ordering: clapper.OrderPermute,
+			args:     []string{"info", "math", "-v", "jane", "--output", "out"},
+			command:  "info",
+			flags:    map[string]string{"verbose": "true", "output": "out"},
+			argVals:  map[string]string{"category": "math", "username": "jane"},
+		},
+		{
+			name:     "require",
+			ordering: clapper.OrderRequire,
+			args:     []string{"info", "-o", "out", "math", "-v", "---l", "--", "x"},
+			command:  "info",
+			flags:    map[string]string{"verbose": "false", "output": "out"},
+			argVals:  map[string]string{"category": "math", "username": "-v", "subjects": "---l,--,x"},
+		},
+		{
+			name:     "require root",
+			ordering: clapper.OrderRequire,
+			args:     []string{"-f", "a", "-v"},
+			flags:    map[string]string{"force": "true", "verbose": "false"},
+			argVals:  map[string]string{"output": "a"},
+		},
+		{
+			name:     "flags before command",
+			ordering: clapper.OrderFlagsBeforeCommand,
+			args:     []string{"-v", "--output", "out", "info", "math", "--output", "-l"},
+			command:  "info",
+			flags:    map[string]string{"verbose": "true", "output": "out"},
+			argVals:  map[string]string{"category": "math", "username": "--output", "subjects": "-l"},
+		},
+		{
+			name:     "flags before command with a command name value",
+			ordering: clapper.OrderFlagsBeforeCommand,
+			args:     []string{"--output", "info", "info", "math"},
+			command:  "info",
+			flags:    map[string]string{"verbose": "false", "output": "info"},
+			argVals:  map[string]string{"category": "math", "username": ""},
+		},
+		{
+			name:     "flags before command root",
+			ordering: clapper.OrderFlagsBeforeCommand,
+			args:     []string{"-v", "a", "b"},
+			flags:    map[string]string{"verbose": "true"},
+			argVals:  map[string]string{"output": "a"},
+		},
+		{
+			name:     "flags before command without arguments",
+			ordering: clapper.OrderFlagsBeforeCommand,
+			args:     []string{"-v", "--", "-a"},
+			flags:    map[string]string{"verbose": "true"},
+			argVals:  map[string]string{"output": "-a"},
+		},
+		{
+			name:     "flags after arguments",
+			ordering: clapper.OrderFlagsAfterArgs,
+			args:     []string{"info", "math", "jane", "-v", "--output", "out"},
+			command:  "info",
+			flags:    map[string]string{"verbose": "true", "output": "out"},
+			argVals:  map[string]string{"category": "math", "username": "jane"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := newDemoRegistry(true)
+			registry.Ordering = tt.ordering
+
+			command := clappertest.Parse(t, registry, tt.args...)
+			clappertest.AssertCommand(t, command, tt.command)
+			clappertest.AssertFlags(t, command, tt.flags)
+			clappertest.AssertArgs(t, command, tt.argVals)
+		})
+	}
+}
+
+// test errors and overrides of the orderings
+func TestOrderingOverrides(t *testing.T) {
+	// an argument after the flags
+	registry := newDemoRegistry(true)
+	registry.Ordering = clapper.OrderFlagsAfterArgs
+	result := clappertest.Run(t, registry, clappertest.Fixture{
+		Args: []string{"info", "math", "-v", "jane"},
+	})
+	clappertest.AssertError(t, result.Err, clapper.ErrorMisplacedArg{Value: "jane"})
+
+	// a flag before the command name selects the root command
+	registry.Ordering = clapper.OrderPermute
+	command := clappertest.Parse(t, registry, "-v", "info", "math")
+	clappertest.AssertCommand(t, command, "")
+	clappertest.AssertArgs(t, command, map[string]string{"output": "info"})
+
+	// POSIXLY_CORRECT environment variable
+	registry.Ordering = clapper.OrderDefault
+	registry.LookupEnv = func(name string) (string, bool) { return "", name == clapper.PosixlyCorrectEnv }
+	command = clappertest.Parse(t, registry, "info", "math", "-v")
+	clappertest.AssertArgs(t, command, map[string]string{"category": "math", "username": "-v"})
+
+	// the command ordering overrides the registry ordering
+	registry.Commands["info"].SetOrdering(clapper.OrderPermute)
+	command = clappertest.Parse(t, registry, "info", "math", "-v")
+	clappertest.AssertFlags(t, command, map[string]string{"verbose": "true"})
+
+	// all values after the command name are arguments
+	registry.Commands["info"].SetOrdering(clapper.OrderFlagsBeforeCommand)
+	command = clappertest.Parse(t, registry, "info", "math", "-v", "-o")
+	clappertest.AssertArgs(t, command, map[string]string{"category": "math", "username": "-v", "subjects": "-o"})
+	if got, want := command.Occurrences[0].Index, 1; got != want {
+		t.Errorf("got index %d, want %d", got, want)
+	}
+
+	// indexes of the occurrences
+	registry = newDemoRegistry(true)
+	registry.Ordering = clapper.OrderFlagsBeforeCommand
+	command = clappertest.Parse(t, registry, "-o=out", "info", "math", "-l")
+	indexes := make([]int, 0)
+	for _, occurrence := range command.Occurrences {
+		indexes = append(indexes, occurrence.Index)
+	}
+	if want := []int{0, 2, 3}; !reflect.DeepEqual(indexes, want) {
+		t.Errorf("got indexes %v, want %v", indexes, want)
+	}
+}
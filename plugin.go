@@ -0,0 +1,159 @@
+package clapper
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// environment variables describing the parent program to a plugin (see `Registry.Plugins`)
+const (
+	// PluginParentEnv is the name of the parent program (see `Registry.Name`).
+	PluginParentEnv = "CLAPPER_PARENT"
+
+	// PluginParentPathEnv is the path of the executable of the parent program (if it is known).
+	PluginParentPathEnv = "CLAPPER_PARENT_PATH"
+
+	// PluginNameEnv is the name of the plugin command.
+	PluginNameEnv = "CLAPPER_PLUGIN"
+)
+
+// return the directories searched for the plugins: `Registry.PluginDirs` and `PATH`
+// the empty and relative entries of `PATH` are skipped, so a plugin is never found in the current directory
+func (registry *Registry) pluginDirs() []string {
+	dirs := append([]string(nil), registry.PluginDirs...)
+	if path, ok := registry.lookupEnv("PATH"); ok {
+		for _, dir := range filepath.SplitList(path) {
+			if dir == "" || !filepath.IsAbs(dir) {
+				continue
+			}
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// check if the file is an executable file, returns the path of the file
+// (with an extension of `PATHEXT` on Windows)
+func executablePath(path string) (string, bool) {
+	if runtime.GOOS == "windows" {
+		exts := strings.Split(strings.ToLower(os.Getenv("PATHEXT")), ";")
+		if len(exts) == 1 && exts[0] == "" {
+			exts = []string{".com", ".exe", ".bat", ".cmd"}
+		}
+		for _, ext := range exts {
+			if ext == "" {
+				continue
+			}
+			if info, err := os.Stat(path + ext); err == nil && info.Mode().IsRegular() {
+				return path + ext, true
+			}
+		}
+		return "", false
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+		return "", false
+	}
+	return path, true
+}
+
+// return the path of the executable of the plugin command (`<program>-<name>`), "" if it is not found
+func (registry *Registry) lookupPlugin(name string) string {
+	if !registry.Plugins || name == "" || isFlag(name) || strings.ContainsAny(name, `/\`) {
+		return ""
+	}
+	if _, ok := registry.Commands[name]; ok {
+		return ""
+	}
+
+	for _, dir := range registry.pluginDirs() {
+		if path, ok := executablePath(filepath.Join(dir, registry.programName()+"-"+name)); ok {
+			return path
+		}
+	}
+
+	return ""
+}
+
+// PluginNames method returns the sorted names of the plugin commands found in the plugin directories
+// (see `Registry.Plugins`), except the names of the registered commands.
+func (registry *Registry) PluginNames() []string {
+	if !registry.Plugins {
+		return []string{}
+	}
+
+	prefix := registry.programName() + "-"
+	found := make(map[string]bool)
+	for _, dir := range registry.pluginDirs() {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			name := strings.TrimPrefix(file.Name(), prefix)
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, filepath.Ext(name))
+			}
+			if !strings.HasPrefix(file.Name(), prefix) || found[name] {
+				continue
+			}
+			if registry.lookupPlugin(name) != "" {
+				found[name] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// RunPlugin method runs the executable of a plugin command returned by `Registry.Parse` (see `CommandParsed.Plugin`)
+// with the values after the command name (`CommandParsed.Passthrough`), the standard streams of the registry
+// and the environment extended with `PluginParentEnv`, `PluginParentPathEnv` and `PluginNameEnv` variables.
+// It returns an `*exec.ExitError` error if the plugin exits with a non-zero status.
+func (registry *Registry) RunPlugin(command *CommandParsed) error {
+	if command.Plugin == "" {
+		return ErrorUnknownCommand{command.Name}
+	}
+
+	cmd := exec.Command(command.Plugin, command.Passthrough...)
+	cmd.Stdin = registry.Stdin
+	if cmd.Stdin == nil {
+		cmd.Stdin = os.Stdin
+	}
+	cmd.Stdout = registry.stdout()
+	cmd.Stderr = registry.stderr()
+
+	cmd.Env = append(os.Environ(), PluginParentEnv+"="+registry.programName(), PluginNameEnv+"="+command.Name)
+	if executable, err := os.Executable(); err == nil {
+		cmd.Env = append(cmd.Env, PluginParentPathEnv+"="+executable)
+	}
+
+	return cmd.Run()
+}
+
+// return the parsed command of a plugin command
+func pluginCommand(name string, path string, values []string) *CommandParsed {
+	return &CommandParsed{
+		Name:        name,
+		Plugin:      path,
+		Flags:       make(map[string]*Flag),
+		Args:        make(map[string]*Arg),
+		ArgNames:    make([]string, 0),
+		Occurrences: make([]*Occurrence, 0),
+		Passthrough: append(make([]string, 0, len(values)), values...),
+		Unknown:     make([]string, 0),
+		Warnings:    make([]string, 0),
+	}
+}
@@ -0,0 +1,134 @@
+package clapper_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/msaf1980/clapper"
+)
+
+// test the plugin commands
+func TestPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin scripts require a POSIX shell")
+	}
+
+	dir, err := ioutil.TempDir("", "clapper-plugins")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFile := func(name string, content string, perm os.FileMode) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), perm); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("prog-hello", "#!/bin/sh\necho \"$CLAPPER_PARENT $CLAPPER_PLUGIN $*\"\n", 0755)
+	writeFile("prog-fail", "#!/bin/sh\nexit 3\n", 0755)
+	writeFile("prog-data", "not executable\n", 0644)
+	writeFile("prog-info", "#!/bin/sh\n", 0755)
+	writeFile("other-tool", "#!/bin/sh\n", 0755)
+
+	registry := newDemoRegistry(true)
+	registry.Name = "prog"
+	registry.Plugins = true
+	registry.PluginDirs = []string{dir}
+	registry.LookupEnv = func(name string) (string, bool) { return "", false }
+	var stdout bytes.Buffer
+	registry.Stdout = &stdout
+
+	// plugins are listed except the registered commands
+	if got, want := registry.PluginNames(), []string{"fail", "hello"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got plugins %q, want %q", got, want)
+	}
+	if got, want := registry.Complete(nil, "he"), []string{"hello"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got candidates %q, want %q", got, want)
+	}
+	help, err := registry.Help("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "  hello   (plugin prog-hello)\n"; !strings.Contains(help, want) {
+		t.Errorf("help %q doesn't contain %q", help, want)
+	}
+
+	// a plugin takes precedence over the arguments of the root command
+	command, err := registry.Parse([]string{"hello", "--name", "x", "---y"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if command.Name != "hello" || command.Plugin != filepath.Join(dir, "prog-hello") {
+		t.Errorf("got command %q (%q), want plugin hello", command.Name, command.Plugin)
+	}
+	if want := []string{"--name", "x", "---y"}; !reflect.DeepEqual(command.Passthrough, want) {
+		t.Errorf("got values %q, want %q", command.Passthrough, want)
+	}
+	if err := registry.RunPlugin(command); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := stdout.String(), "prog hello --name x ---y\n"; got != want {
+		t.Errorf("got output %q, want %q", got, want)
+	}
+
+	// the exit status of the plugin
+	command, err = registry.Parse([]string{"fail"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err, ok := registry.RunPlugin(command).(*exec.ExitError); !ok || err.ExitCode() != 3 {
+		t.Errorf("got error %v, want exit status 3", err)
+	}
+
+	// registered commands and not executable files are not plugins
+	command, err = registry.Parse([]string{"info", "student"})
+	if err != nil || command.Plugin != "" {
+		t.Errorf("got plugin %q (%v), want info command", command.Plugin, err)
+	}
+	command, err = registry.Parse([]string{"data"})
+	if err != nil || command.Name != "" || command.Plugin != "" {
+		t.Errorf("got command %q (%v), want root command", command.Name, err)
+	}
+
+	// plugins are disabled by default
+	registry.Plugins = false
+	registry.Commands[""].Args = nil
+	registry.Commands[""].ArgNames = nil
+	if _, err := registry.Parse([]string{"hello"}); err != (clapper.ErrorUnknownCommand{Name: "hello"}) {
+		t.Errorf("got error %v, want unknown command", err)
+	}
+
+	// plugins are found in `PATH`
+	registry.Plugins = true
+	registry.PluginDirs = nil
+	registry.LookupEnv = func(name string) (string, bool) { return dir, name == "PATH" }
+	if command, err := registry.Parse([]string{"hello"}); err != nil || command.Plugin == "" {
+		t.Errorf("got plugin %q (%v), want hello plugin", command.Plugin, err)
+	}
+
+	// empty and relative entries of `PATH` are skipped
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(cwd)
+	registry.LookupEnv = func(name string) (string, bool) {
+		return string(os.PathListSeparator) + ".", name == "PATH"
+	}
+	if got := registry.PluginNames(); len(got) != 0 {
+		t.Errorf("got plugins %q, want none", got)
+	}
+	if command, err := registry.Parse([]string{"hello"}); err != (clapper.ErrorUnknownCommand{Name: "hello"}) {
+		t.Errorf("got command %q (%q, %v), want unknown command", command.Name, command.Plugin, err)
+	}
+}
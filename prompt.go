@@ -0,0 +1,264 @@
+package clapper
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Prompter type holds the configuration of the interactive prompting for missing values.
+// Values of the required flags and arguments missing in the command-line arguments are asked in the registration order
+// (flags sorted by name first, then arguments). A value with valid values is selected from a numbered menu,
+// a boolean flag is confirmed with yes/no and a sensitive flag is read without echo.
+type Prompter struct {
+
+	// reader of the answers
+	// if nil, answers are read from `Registry.Stdin` (`os.Stdin` if nil), and values are prompted from `os.Stdin`
+	// only if it is a terminal
+	In io.Reader
+
+	// writer of the questions (`os.Stderr` if nil)
+	Out io.Writer
+
+	// function disabling the echo of the input while a sensitive value is read, it returns a function restoring the echo
+	// if nil and the answers are read from the terminal, the echo of the terminal is disabled (if supported by the platform)
+	HideInput func() (restore func(), err error)
+}
+
+// ErrorPrompt represents an error when the prompter can't read a missing value of a required flag or argument
+// (the end of the input is `ErrorMissingFlag` or `ErrorMissingArg` error).
+type ErrorPrompt struct {
+	Name string
+	Err  error
+}
+
+func (e ErrorPrompt) Error() string {
+	return fmt.Sprintf("can't prompt value of %s: %v", e.Name, e.Err)
+}
+
+// Unwrap returns the cause of the error.
+func (e ErrorPrompt) Unwrap() error {
+	return e.Err
+}
+
+/*---------------------*/
+
+// prompting session of a single `Parse` call
+type prompt struct {
+	in        *bufio.Reader
+	out       io.Writer
+	hideInput func() (restore func(), err error)
+	messages  localizer
+}
+
+// start a prompting session reading the answers from `In` or the standard input of the registry,
+// returns nil if prompting is not possible
+func (prompter *Prompter) start(messages localizer, stdin io.Reader) *prompt {
+	if prompter == nil {
+		return nil
+	}
+
+	p := &prompt{
+		out:       prompter.Out,
+		hideInput: prompter.HideInput,
+		messages:  messages,
+	}
+
+	in := prompter.In
+	if in == nil {
+		in = stdin
+	}
+	p.in = bufio.NewReader(in)
+
+	// the standard input of the process is prompted only if it is a terminal
+	if file, ok := in.(*os.File); ok && prompter.In == nil {
+		if !isTerminal(file) {
+			return nil
+		}
+		if p.hideInput == nil {
+			p.hideInput = func() (func(), error) {
+				return disableEcho(file)
+			}
+		}
+	}
+
+	if p.out == nil {
+		p.out = os.Stderr
+	}
+
+	return p
+}
+
+// read an answer line (without the line ending)
+func (p *prompt) readLine(hidden bool) (string, error) {
+	if hidden && p.hideInput != nil {
+		restore, err := p.hideInput()
+		if err != nil {
+			return "", err
+		}
+		defer func() {
+			restore()
+			fmt.Fprintln(p.out) // the line ending of the answer is not echoed
+		}()
+	}
+
+	line, err := p.in.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+// ask a value until a valid one is entered
+// `title` is the name of the value, `validVals` is a list of the valid values (empty list accepts any value)
+// `defaultValue` is used for an empty answer
+func (p *prompt) ask(title string, defaultValue string, validVals []string, hidden bool) (string, error) {
+	for {
+		// valid values of a hidden input are not listed
+		menu := len(validVals) > 0 && !hidden
+
+		if menu {
+			fmt.Fprintf(p.out, "%s:\n", title)
+			for i, v := range validVals {
+				fmt.Fprintf(p.out, "  %d) %s\n", i+1, v)
+			}
+			fmt.Fprint(p.out, p.messages.format("prompt.select", len(validVals)))
+		} else {
+			fmt.Fprint(p.out, title)
+		}
+		if defaultValue != "" && !hidden {
+			fmt.Fprint(p.out, " "+p.messages.format("help.default", defaultValue))
+		}
+		fmt.Fprint(p.out, ": ")
+
+		answer, err := p.readLine(hidden)
+		if err != nil {
+			return "", err
+		}
+
+		if answer == "" {
+			if defaultValue != "" {
+				return defaultValue, nil
+			}
+			continue
+		}
+
+		if len(validVals) == 0 {
+			return answer, nil
+		}
+
+		// a number of the menu item or a valid value itself
+		if n, err := strconv.Atoi(answer); menu && err == nil && n >= 1 && n <= len(validVals) {
+			return validVals[n-1], nil
+		}
+		for _, v := range validVals {
+			if v == answer {
+				return v, nil
+			}
+		}
+
+		if hidden {
+			answer = RedactedValue
+		}
+		fmt.Fprintln(p.out, p.messages.format("prompt.invalid", answer))
+	}
+}
+
+// ask a yes/no question
+func (p *prompt) confirm(title string, defaultValue bool) (bool, error) {
+	choices := "y/N"
+	if defaultValue {
+		choices = "Y/n"
+	}
+
+	for {
+		fmt.Fprintf(p.out, "%s? [%s]: ", title, choices)
+
+		answer, err := p.readLine(false)
+		if err != nil {
+			return false, err
+		}
+
+		switch strings.ToLower(answer) {
+		case "":
+			return defaultValue, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+	}
+}
+
+// ask a value of the flag
+func (p *prompt) askFlag(flag *FlagCommand) (string, error) {
+	if flag.IsBoolean {
+		name := flag.Name
+		if flag.IsInverted {
+			name = "no-" + name
+		}
+
+		// an inverted flag is confirmed by its `no-` name
+		yes, err := p.confirm(name, false)
+		if err != nil {
+			return "", err
+		}
+
+		return strconv.FormatBool(yes != flag.IsInverted), nil
+	}
+
+	return p.ask(flag.Name, flag.DefaultValue, flag.ValidValsList(), flag.Sensitive)
+}
+
+// check the values of the required flags and arguments, ask the missing values if prompting is enabled
+func (registry *Registry) requireValues(commandConfig *CommandConfig, store *CommandParsed) error {
+	var p *prompt
+
+	for _, name := range commandConfig.FlagNames() {
+		flag := commandConfig.Flags[name]
+		if _, ok := store.Flags[name]; ok || !flag.Required {
+			continue
+		}
+
+		if p == nil {
+			if p = registry.Prompter.start(registry.messages(), registry.stdin()); p == nil {
+				return ErrorMissingFlag{name}
+			}
+		}
+
+		value, err := p.askFlag(flag)
+		if err == io.EOF {
+			return ErrorMissingFlag{name}
+		} else if err != nil {
+			return ErrorPrompt{name, err}
+		}
+		store.Flags[name] = flag.StoreFrom(value, SourcePrompt, "")
+	}
+
+	for _, name := range commandConfig.ArgNames {
+		arg := commandConfig.Args[name]
+		if stored, ok := store.Args[name]; (ok && stored.Value != "") || !arg.Required {
+			continue
+		}
+
+		if p == nil {
+			if p = registry.Prompter.start(registry.messages(), registry.stdin()); p == nil {
+				return ErrorMissingArg{name}
+			}
+		}
+
+		value, err := p.ask(name, arg.DefaultValue, arg.ValidValsList(), false)
+		if err == io.EOF {
+			return ErrorMissingArg{name}
+		} else if err != nil {
+			return ErrorPrompt{name, err}
+		}
+		store.Args[name] = arg.StoreFrom(value, SourcePrompt)
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package clapper_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/msaf1980/clapper"
+	"github.com/msaf1980/clapper/clappertest"
+)
+
+// test missing required values without prompting
+func TestRequiredValues(t *testing.T) {
+	registry := newDemoRegistry(false)
+	infoCommand := registry.Commands["info"]
+	infoCommand.Flags["output"].SetRequired(true)
+	infoCommand.Args["category"].SetRequired(true)
+	infoCommand.Args["category"].DefaultValue = ""
+
+	result := clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"info", "student"}})
+	clappertest.AssertError(t, result.Err, clapper.ErrorMissingFlag{Name: "output"})
+
+	result = clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"info", "-o", "me"}})
+	clappertest.AssertError(t, result.Err, clapper.ErrorMissingArg{Name: "category"})
+
+	command := clappertest.Parse(t, registry, "info", "student", "-o", "me")
+	clappertest.AssertArgs(t, command, map[string]string{"category": "student", "username": ""})
+}
+
+// test prompting of missing values
+func TestPromptValues(t *testing.T) {
+	registry := newDemoRegistry(false)
+	infoCommand := registry.Commands["info"]
+	infoCommand.Flags["clean"].SetRequired(true)
+	infoCommand.Flags["output"].SetRequired(true)
+	token, _ := infoCommand.AddFlag("token", "", false, "")
+	token.SetRequired(true).SetSensitive(true)
+	infoCommand.Args["category"].SetRequired(true)
+	infoCommand.Args["category"].DefaultValue = ""
+
+	hidden := 0
+	var out bytes.Buffer
+	registry.Prompter = &clapper.Prompter{
+		// no-clean: no, output: default, token: secret, category: invalid and then the second item
+		In:  strings.NewReader("n\n\nsecret\nteacher\n2\n"),
+		Out: &out,
+		HideInput: func() (func(), error) {
+			hidden++
+			return func() {}, nil
+		},
+	}
+
+	command := clappertest.Parse(t, registry, "info")
+	clappertest.AssertFlags(t, command, map[string]string{
+		"clean":  "true",
+		"output": "./",
+		"token":  "secret",
+	})
+	clappertest.AssertArgs(t, command, map[string]string{"category": "student"})
+
+	if hidden != 1 {
+		t.Errorf("input is hidden %d times, want 1", hidden)
+	}
+	for _, name := range []string{"clean", "output", "token"} {
+		if source := command.Flags[name].Source; source != clapper.SourcePrompt {
+			t.Errorf("flag(%s) source is %q", name, source)
+		}
+	}
+
+	menu := "category:\n  1) manager\n  2) student\n  3) thatisuday\n  4) math\n  5) science\n  6) physics\nSelect [1-6]: "
+	want := "no-clean? [y/N]: " +
+		"output (default: ./): " +
+		"token: \n" +
+		menu +
+		"invalid value teacher\n" +
+		menu
+	if out.String() != want {
+		t.Errorf("got\n%q\nwant\n%q", out.String(), want)
+	}
+
+	// end of input
+	registry.Prompter.In = strings.NewReader("y\n")
+	result := clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"info"}})
+	clappertest.AssertError(t, result.Err, clapper.ErrorMissingFlag{Name: "output"})
+
+	// the prompter fails
+	cause := errors.New("no terminal")
+	registry.Prompter.In = strings.NewReader("y\n\n")
+	registry.Prompter.HideInput = func() (func(), error) {
+		return nil, cause
+	}
+	result = clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"info"}})
+	if !errors.Is(result.Err, cause) {
+		t.Fatalf("got %#v", result.Err)
+	}
+	clappertest.AssertError(t, result.Err, clapper.ErrorPrompt{Name: "token", Err: cause})
+}
+
+// test prompting from the standard input of the registry
+func TestPromptStdin(t *testing.T) {
+	registry := newDemoRegistry(false)
+	registry.Commands["info"].Flags["output"].SetRequired(true)
+	var out bytes.Buffer
+	registry.Prompter = &clapper.Prompter{Out: &out}
+	registry.Stdin = strings.NewReader("/tmp\n")
+
+	command := clappertest.Parse(t, registry, "info")
+	clappertest.AssertFlags(t, command, map[string]string{"output": "/tmp"})
+	if got, want := out.String(), "output (default: ./): "; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// a standard input which is not a terminal is not prompted
+	file, err := ioutil.TempFile("", "clapper-stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+	if _, err := file.WriteString("/tmp\n"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.Seek(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	registry.Stdin = file
+	result := clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"info"}})
+	clappertest.AssertError(t, result.Err, clapper.ErrorMissingFlag{Name: "output"})
+}
@@ -0,0 +1,163 @@
+package clapper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OverlapPolicy type describes the resolution of a value which is a command name
+// and a value of an argument of the root command (see `Registry.Overlap`).
+type OverlapPolicy int
+
+const (
+	// OverlapCommand resolves the value to the command (default),
+	// use `./<value>` or `-- <value>` to pass it to an argument of the root command
+	OverlapCommand OverlapPolicy = iota
+
+	// OverlapArg resolves the value to an argument of the root command if an argument accepts it
+	// (see `ArgCommand.SetValidVals`, an argument without valid values accepts any value)
+	OverlapArg
+
+	// OverlapError returns `ErrorAmbiguousCommand` error if an argument of the root command accepts the value
+	OverlapError
+)
+
+// ErrorAmbiguousCommand represents an error when a command name is a value of an argument of the root command
+// with `OverlapError` policy.
+type ErrorAmbiguousCommand struct {
+	Name string
+}
+
+func (e ErrorAmbiguousCommand) Error() string {
+	return fmt.Sprintf("ambiguous command %s found in the arguments (use -- %s for an argument)", e.Name, e.Name)
+}
+
+// names of the overlap policies used in the schema (see `OverlapPolicy.MarshalText`)
+var overlapNames = []string{"command", "arg", "error"}
+
+// String method returns the name of the overlap policy, for example `arg`.
+func (overlap OverlapPolicy) String() string {
+	if overlap < OverlapCommand || int(overlap) >= len(overlapNames) {
+		return fmt.Sprintf("OverlapPolicy(%d)", int(overlap))
+	}
+	return overlapNames[overlap]
+}
+
+// MarshalText method implements `encoding.TextMarshaler` interface, the overlap policy is serialized by name.
+func (overlap OverlapPolicy) MarshalText() ([]byte, error) {
+	if overlap < OverlapCommand || int(overlap) >= len(overlapNames) {
+		return nil, fmt.Errorf("invalid overlap policy %d", int(overlap))
+	}
+	return []byte(overlapNames[overlap]), nil
+}
+
+// UnmarshalText method implements `encoding.TextUnmarshaler` interface.
+func (overlap *OverlapPolicy) UnmarshalText(text []byte) error {
+	for i, name := range overlapNames {
+		if name == string(text) {
+			*overlap = OverlapPolicy(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid overlap policy %q", text)
+}
+
+/*---------------------*/
+
+// return the number of the leading flags (and their values) of the values
+// a flag takes the next value only if it is a non-boolean flag of the root command
+func (registry *Registry) leadingFlags(values []string) int {
+	rootCommandConfig := registry.Commands[""]
+
+	index := 0
+	for index < len(values) {
+		value := registry.canonicalFlag(rootCommandConfig, values[index])
+		if !isFlag(value) || isEndOfFlags(value) {
+			break
+		}
+		index++
+
+		if flag := rootCommandConfig.lookupFlagValue(value); flag != nil && !flag.IsBoolean && !strings.Contains(value, "=") &&
+			index < len(values) && !isFlag(values[index]) {
+			index++
+		}
+	}
+
+	return index
+}
+
+// return a command holding the global flags of the root command which are not flags of the command
+func (commandConfig *CommandConfig) globalFlags(rootCommandConfig *CommandConfig) *CommandConfig {
+	global := &CommandConfig{Flags: make(map[string]*FlagCommand)}
+	for name, flag := range rootCommandConfig.Flags {
+		if _, ok := commandConfig.Flags[name]; !ok {
+			global.Flags[name] = flag
+		}
+	}
+	return global
+}
+
+// return the registered flag of a flag value (nil for the nil command)
+func (commandConfig *CommandConfig) lookupFlagValue(value string) *FlagCommand {
+	if commandConfig == nil {
+		return nil
+	}
+	return commandConfig.lookupFlag(value)
+}
+
+// check if an argument of the command accepts the value
+func (commandConfig *CommandConfig) acceptsArg(value string) bool {
+	for _, name := range commandConfig.ArgNames {
+		if commandConfig.Args[name].Validate(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// find the command name in the values, returns the name and its index in the values (-1 for the root command)
+//
+// The command name is the first value unless it is a flag or `--` (the root command).
+// With `Registry.SkipLeadingFlags`, it is the first value after the leading flags.
+// A value which is not a registered command (or a plugin) is an argument of the root command
+// if the root command has arguments, so `./<value>` or `-- <value>` is never a command name.
+// A value which is a command name and a value of an argument of the root command is resolved by `Registry.Overlap`.
+func (registry *Registry) resolveCommand(values []string) (string, int, error) {
+	rootCommandConfig, hasRoot := registry.Commands[""]
+
+	index := 0
+	if registry.SkipLeadingFlags {
+		index = registry.leadingFlags(values)
+	}
+
+	// the first value is the command name if the root command is not registered
+	if !hasRoot && (!registry.SkipLeadingFlags || index < len(values)) {
+		if len(values) == 0 {
+			return "", -1, nil
+		}
+		return values[index], index, nil
+	}
+
+	if index >= len(values) || isFlag(registry.canonicalFlag(rootCommandConfig, values[index])) {
+		return "", -1, nil
+	}
+
+	value := values[index]
+	_, isCommand := registry.Commands[value]
+	isCommand = isCommand || registry.lookupPlugin(value) != ""
+	isArg := len(rootCommandConfig.Args) > 0
+
+	switch {
+	case !isCommand && isArg:
+		return "", -1, nil
+	case isCommand && isArg && rootCommandConfig.acceptsArg(value):
+		switch registry.Overlap {
+		case OverlapArg:
+			return "", -1, nil
+		case OverlapError:
+			return "", -1, ErrorAmbiguousCommand{value}
+		}
+	}
+
+	return value, index, nil
+}
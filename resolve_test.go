@@ -0,0 +1,152 @@
+package clapper_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/msaf1980/clapper"
+	"github.com/msaf1980/clapper/clappertest"
+)
+
+// test the resolution of the command name
+func TestResolveCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(registry *clapper.Registry)
+		args    []string
+		command string
+		argVals map[string]string
+		flags   map[string]string
+	}{
+		{
+			name:    "command",
+			args:    []string{"info", "student"},
+			command: "info",
+			argVals: map[string]string{"category": "student"},
+		},
+		{
+			name:    "root argument",
+			args:    []string{"data.txt"},
+			argVals: map[string]string{"output": "data.txt"},
+		},
+		{
+			name:    "escaped path",
+			args:    []string{"./info", "-v"},
+			argVals: map[string]string{"output": "./info"},
+		},
+		{
+			name:    "escaped end of flags",
+			args:    []string{"-v", "--", "info"},
+			argVals: map[string]string{"output": "info"},
+		},
+		{
+			name:    "leading flag",
+			args:    []string{"-v", "info"},
+			argVals: map[string]string{"output": "info"},
+		},
+		{
+			name:    "skip leading flags",
+			setup:   func(registry *clapper.Registry) { registry.SkipLeadingFlags = true },
+			args:    []string{"-v", "--dir", "/tmp", "info", "student"},
+			command: "info",
+			argVals: map[string]string{"category": "student"},
+			flags:   map[string]string{"verbose": "true", "dir": "/tmp"},
+		},
+		{
+			name:    "skip leading flags with assignment",
+			setup:   func(registry *clapper.Registry) { registry.SkipLeadingFlags = true },
+			args:    []string{"--dir=/tmp", "info"},
+			command: "info",
+			argVals: map[string]string{"category": "manager"},
+			flags:   map[string]string{"verbose": "false", "dir": "/tmp"},
+		},
+		{
+			name:    "skip leading flags to the root argument",
+			setup:   func(registry *clapper.Registry) { registry.SkipLeadingFlags = true },
+			args:    []string{"-v", "data.txt"},
+			argVals: map[string]string{"output": "data.txt"},
+		},
+		{
+			name: "overlap argument",
+			setup: func(registry *clapper.Registry) {
+				registry.Overlap = clapper.OverlapArg
+				registry.Commands[""].Args["output"].SetValidVals([]string{"info", "data.txt"})
+			},
+			args:    []string{"info"},
+			argVals: map[string]string{"output": "info"},
+		},
+		{
+			name: "overlap argument with invalid value",
+			setup: func(registry *clapper.Registry) {
+				registry.Overlap = clapper.OverlapArg
+				registry.Commands[""].Args["output"].SetValidVals([]string{"data.txt"})
+			},
+			args:    []string{"info", "student"},
+			command: "info",
+			argVals: map[string]string{"category": "student"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := newDemoRegistry(true)
+			if tt.setup != nil {
+				tt.setup(registry)
+			}
+
+			command := clappertest.Parse(t, registry, tt.args...)
+			clappertest.AssertCommand(t, command, tt.command)
+			clappertest.AssertArgs(t, command, tt.argVals)
+			if tt.flags != nil {
+				clappertest.AssertFlags(t, command, tt.flags)
+			}
+		})
+	}
+
+	// the ambiguous command
+	registry := newDemoRegistry(true)
+	registry.Overlap = clapper.OverlapError
+	result := clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"info"}})
+	clappertest.AssertError(t, result.Err, clapper.ErrorAmbiguousCommand{Name: "info"})
+
+	// the indexes of the occurrences skip the command name
+	registry = newDemoRegistry(true)
+	registry.SkipLeadingFlags = true
+	command := clappertest.Parse(t, registry, "--dir", "x", "info", "-v", "student")
+	indexes := make([]int, 0)
+	for _, occurrence := range command.Occurrences {
+		indexes = append(indexes, occurrence.Index)
+	}
+	if want := []int{0, 3, 4}; !reflect.DeepEqual(indexes, want) {
+		t.Errorf("got indexes %v, want %v", indexes, want)
+	}
+
+	// a global flag is parsed by the flags of the root command
+	limit := 0
+	limitFlag, _ := registry.Commands[""].AddFlag("limit", "", false, "10")
+	limitFlag.SetFlagValue(intValue{&limit})
+	registry.Commands["info"].AddFlag("force", "f", false, "")
+
+	command = clappertest.Parse(t, registry, "-f", "--limit", "5", "info", "student")
+	clappertest.AssertCommand(t, command, "info")
+	clappertest.AssertFlags(t, command, map[string]string{"force": "true", "limit": "5"})
+	clappertest.AssertArgs(t, command, map[string]string{"category": "student"})
+	if limit != 5 {
+		t.Errorf("got global flag value %d, want 5", limit)
+	}
+
+	command = clappertest.Parse(t, registry, "info", "-f", "yes", "student")
+	clappertest.AssertFlags(t, command, map[string]string{"force": "yes", "limit": "10"})
+
+	// the leading flags of an alternative syntax are the flags of the root command
+	registry.Syntaxes = []clapper.FlagSyntax{clapper.SlashSyntax{}}
+	command = clappertest.Parse(t, registry, "/force", "/limit:7", "info", "/v", "student")
+	clappertest.AssertCommand(t, command, "info")
+	clappertest.AssertFlags(t, command, map[string]string{"force": "true", "limit": "7", "verbose": "true"})
+	clappertest.AssertArgs(t, command, map[string]string{"category": "student"})
+
+	// completion of the command name after the leading flags
+	if got, want := registry.Complete([]string{"-v"}, "in"), []string{"info"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got candidates %q, want %q", got, want)
+	}
+}
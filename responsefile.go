@@ -0,0 +1,173 @@
+package clapper
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// MaxResponseFileDepth is the maximum nesting level of response files.
+const MaxResponseFileDepth = 10
+
+// ErrorResponseFile represents an error when a response file can't be expanded.
+// The `File` and `Line` fields are the location of the error (`Line` is 0 if the error is not bound to a line).
+type ErrorResponseFile struct {
+	File string
+	Line int
+	Err  error
+}
+
+func (e ErrorResponseFile) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("response file %s, line %d: %v", e.File, e.Line, e.Err)
+	}
+	return fmt.Sprintf("response file %s: %v", e.File, e.Err)
+}
+
+// Unwrap returns the cause of the error.
+func (e ErrorResponseFile) Unwrap() error {
+	return e.Err
+}
+
+// ErrorResponseFileCycle represents an error when a response file includes itself directly or indirectly.
+type ErrorResponseFileCycle struct {
+	File string
+}
+
+func (e ErrorResponseFileCycle) Error() string {
+	return fmt.Sprintf("response file %s includes itself", e.File)
+}
+
+// ErrorResponseFileDepth represents an error when response files are nested deeper than `MaxResponseFileDepth`.
+type ErrorResponseFileDepth struct {
+	File string
+}
+
+func (e ErrorResponseFileDepth) Error() string {
+	return fmt.Sprintf("response file %s is nested deeper than %d levels", e.File, MaxResponseFileDepth)
+}
+
+/*---------------------*/
+
+// check if value is a response file reference `@path`
+func isResponseFile(value string) (bool, string) {
+	if len(value) > 1 && strings.HasPrefix(value, "@") {
+		return true, value[1:]
+	}
+	return false, ""
+}
+
+// read and expand a response file, returns `true` if the values contain the `--` marker
+// `includes` holds the absolute paths of the files being expanded (for the cycle detection)
+func expandResponseFile(path string, includes []string) ([]string, bool, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, include := range includes {
+		if include == absPath {
+			return nil, false, ErrorResponseFileCycle{path}
+		}
+	}
+	if len(includes) >= MaxResponseFileDepth {
+		return nil, false, ErrorResponseFileDepth{path}
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	tokens, err := tokenize(string(data))
+	if err != nil {
+		if quoteErr, ok := err.(ErrorUnterminatedQuote); ok {
+			return nil, false, ErrorResponseFile{path, quoteErr.Line, err}
+		}
+		return nil, false, ErrorResponseFile{path, 0, err}
+	}
+
+	includes = append(includes, absPath)
+
+	values := make([]string, 0, len(tokens))
+	for i, token := range tokens {
+		if isEndOfFlags(token.value) {
+			for _, token := range tokens[i:] {
+				values = append(values, token.value)
+			}
+			return values, true, nil
+		}
+
+		ok, include := isResponseFile(token.value)
+		if !ok {
+			values = append(values, token.value)
+			continue
+		}
+
+		// nested response files are relative to the directory of the including file
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(path), include)
+		}
+
+		expanded, endOfFlags, err := expandResponseFile(include, includes)
+		if err != nil {
+			if _, ok := err.(ErrorResponseFile); ok {
+				return nil, false, err
+			}
+			return nil, false, ErrorResponseFile{path, token.line, err}
+		}
+		values = append(values, expanded...)
+
+		// the values after the `--` marker of a nested response file are not expanded
+		if endOfFlags {
+			for _, token := range tokens[i+1:] {
+				values = append(values, token.value)
+			}
+			return values, true, nil
+		}
+	}
+
+	return values, false, nil
+}
+
+// ExpandResponseFiles replaces the `@path` values with the arguments read from the file `path`.
+// Arguments in a file are separated by whitespaces and newlines and can be quoted using POSIX shell quoting rules,
+// a `#` at the beginning of an argument starts a comment until the end of the line.
+// Response files can be nested up to `MaxResponseFileDepth` levels, a relative path in a response file is relative
+// to the directory of that file. Values after the `--` marker (in the arguments or in a response file) are not expanded.
+// All errors are returned as `ErrorResponseFile` with the location of the failed `@path` value (if any)
+// and the cause (`ErrorResponseFileCycle`, `ErrorResponseFileDepth`, `ErrorUnterminatedQuote` or an I/O error).
+func ExpandResponseFiles(values []string) ([]string, error) {
+	expanded := make([]string, 0, len(values))
+
+	for i, value := range values {
+		if isEndOfFlags(value) {
+			expanded = append(expanded, values[i:]...)
+			break
+		}
+
+		ok, path := isResponseFile(value)
+		if !ok {
+			expanded = append(expanded, value)
+			continue
+		}
+
+		fileValues, endOfFlags, err := expandResponseFile(path, nil)
+		if err != nil {
+			if _, ok := err.(ErrorResponseFile); ok {
+				return nil, err
+			}
+			return nil, ErrorResponseFile{path, 0, err}
+		}
+		expanded = append(expanded, fileValues...)
+
+		// the values after the `--` marker of a response file are not expanded
+		if endOfFlags {
+			expanded = append(expanded, values[i+1:]...)
+			break
+		}
+	}
+
+	return expanded, nil
+}
@@ -0,0 +1,160 @@
+package clapper_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/msaf1980/clapper"
+	"github.com/msaf1980/clapper/clappertest"
+)
+
+// test expansion of response files
+func TestResponseFiles(t *testing.T) {
+	registry := newDemoRegistry(false)
+	registry.ResponseFiles = true
+
+	result := clappertest.Run(t, registry, clappertest.Fixture{
+		Args: []string{"info", "@" + clappertest.DirPlaceholder + "/args.txt", "last", "--", "@not-a-file"},
+		Files: map[string]string{
+			"args.txt": "# category\n" +
+				"student\n" +
+				"--output 'my dir' \"quoted \\\"value\\\"\"  # comment\n" +
+				"@nested/more.txt\n",
+			"nested/more.txt": "math sci\\ ence",
+		},
+	})
+	if result.Err != nil {
+		t.Fatal(result.Err)
+	}
+
+	clappertest.AssertArgs(t, result.Command, map[string]string{
+		"category": "student",
+		"username": `quoted "value"`,
+		"subjects": "math,sci ence,last,@not-a-file",
+	})
+	clappertest.AssertFlags(t, result.Command, map[string]string{
+		"output": "my dir",
+	})
+}
+
+// test the `--` marker of a response file stops the expansion of the following values
+func TestResponseFileEndOfFlags(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clapper-responsefiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{"a.txt": "x @c.txt @b.txt y", "b.txt": "b", "c.txt": "z -- @b.txt"}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b := "@" + filepath.Join(dir, "b.txt")
+	values, err := clapper.ExpandResponseFiles([]string{"@" + filepath.Join(dir, "a.txt"), b, "w"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"x", "z", "--", "@b.txt", "@b.txt", "y", b, "w"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("got values %q, want %q", values, want)
+	}
+}
+
+// test response file errors
+func TestResponseFileErrors(t *testing.T) {
+	tests := map[string]struct {
+		files map[string]string
+		file  string
+		line  int
+		cause error
+	}{
+		"cycle": {
+			files: map[string]string{"args.txt": "a\n@b.txt", "b.txt": "\n\n@args.txt"},
+			file:  "b.txt",
+			line:  3,
+			cause: clapper.ErrorResponseFileCycle{},
+		},
+		"quote": {
+			files: map[string]string{"args.txt": "a\n  'b\nc"},
+			file:  "args.txt",
+			line:  2,
+			cause: clapper.ErrorUnterminatedQuote{Quote: '\'', Line: 2, Column: 3},
+		},
+		"missing": {
+			files: map[string]string{"args.txt": "a @missing.txt"},
+			file:  "args.txt",
+			line:  1,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			registry := newDemoRegistry(false)
+			registry.ResponseFiles = true
+
+			result := clappertest.Run(t, registry, clappertest.Fixture{
+				Args:  []string{"info", "@" + clappertest.DirPlaceholder + "/args.txt"},
+				Files: tt.files,
+			})
+
+			var err clapper.ErrorResponseFile
+			if !errors.As(result.Err, &err) {
+				t.Fatalf("got %#v", result.Err)
+			}
+			if filepath.Base(err.File) != tt.file || err.Line != tt.line {
+				t.Errorf("got location %s:%d, want %s:%d", err.File, err.Line, tt.file, tt.line)
+			}
+
+			switch cause := tt.cause.(type) {
+			case nil:
+				if !os.IsNotExist(err.Err) {
+					t.Errorf("got cause %#v", err.Err)
+				}
+			case clapper.ErrorResponseFileCycle:
+				if _, ok := err.Err.(clapper.ErrorResponseFileCycle); !ok {
+					t.Errorf("got cause %#v", err.Err)
+				}
+			default:
+				if err.Err != cause {
+					t.Errorf("got cause %#v, want %#v", err.Err, cause)
+				}
+			}
+		})
+	}
+}
+
+// test nesting limit of response files
+func TestResponseFileDepth(t *testing.T) {
+	files := make(map[string]string)
+	for i := 0; i <= clapper.MaxResponseFileDepth; i++ {
+		files[fmt.Sprintf("%d.txt", i)] = fmt.Sprintf("v%d @%d.txt", i, i+1)
+	}
+
+	registry := newDemoRegistry(false)
+	registry.ResponseFiles = true
+
+	result := clappertest.Run(t, registry, clappertest.Fixture{
+		Args:  []string{"info", "@" + clappertest.DirPlaceholder + "/0.txt"},
+		Files: files,
+	})
+
+	var err clapper.ErrorResponseFile
+	if !errors.As(result.Err, &err) {
+		t.Fatalf("got %#v", result.Err)
+	}
+	if _, ok := err.Err.(clapper.ErrorResponseFileDepth); !ok || !strings.HasSuffix(err.File, fmt.Sprintf("%d.txt", clapper.MaxResponseFileDepth-1)) {
+		t.Fatalf("got %v", err)
+	}
+
+	// disabled by default
+	command := clappertest.Parse(t, newDemoRegistry(false), "info", "student", "@args.txt")
+	clappertest.AssertArgs(t, command, map[string]string{"category": "student", "username": "@args.txt"})
+}
@@ -0,0 +1,372 @@
+package clapper
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SchemaVersion is the version of the JSON schema produced by `Registry.ExportSchema`.
+// It is incremented on every incompatible change of the schema.
+const SchemaVersion = 1
+
+// ErrorSchemaVersion represents an error when a schema document has an unsupported version.
+type ErrorSchemaVersion struct {
+	Version int
+}
+
+func (e ErrorSchemaVersion) Error() string {
+	return fmt.Sprintf("unsupported schema version %d (supported version is %d)", e.Version, SchemaVersion)
+}
+
+// ErrorSchemaInvalid represents an error when a schema document contains an invalid definition.
+type ErrorSchemaInvalid struct {
+	Command string
+	Reason  string
+}
+
+func (e ErrorSchemaInvalid) Error() string {
+	return fmt.Sprintf("invalid schema for command %q: %s", e.Command, e.Reason)
+}
+
+/*---------------------*/
+
+// Schema type holds the serializable definition of a registry.
+// The options of the registry which are code or depend on the environment (`Syntaxes`, `Prompter`, `LookupEnv`,
+// `Configs`, `PluginDirs`, the locale, the colors and the I/O) are not part of the schema.
+type Schema struct {
+	// version of the schema
+	Version int `json:"version"`
+
+	// name of the program (see `Registry.Name`)
+	Name string `json:"name,omitempty"`
+
+	// placement of the flags and arguments of the registry (see `Registry.Ordering`)
+	Ordering Ordering `json:"ordering,omitempty"`
+
+	// if the command name is found after the leading flags (see `Registry.SkipLeadingFlags`)
+	SkipLeadingFlags bool `json:"skipLeadingFlags,omitempty"`
+
+	// resolution of a command name accepted by an argument of the root command (see `Registry.Overlap`)
+	Overlap OverlapPolicy `json:"overlap,omitempty"`
+
+	// if unregistered flags are collected by all commands (see `Registry.AllowUnknownFlags`)
+	AllowUnknownFlags bool `json:"allowUnknownFlags,omitempty"`
+
+	// if the `--dump-args` flag is accepted (see `Registry.DumpArgs`)
+	DumpArgs bool `json:"dumpArgs,omitempty"`
+
+	// if the `--help` and `-h` flags are accepted (see `Registry.HelpFlags`)
+	HelpFlags bool `json:"helpFlags,omitempty"`
+
+	// if the `@path` values are expanded (see `Registry.ResponseFiles`)
+	ResponseFiles bool `json:"responseFiles,omitempty"`
+
+	// if the plugin commands are resolved (see `Registry.Plugins`)
+	Plugins bool `json:"plugins,omitempty"`
+
+	// registered commands, sorted by name (the root command has an empty name)
+	Commands []*SchemaCommand `json:"commands"`
+}
+
+// SchemaCommand type holds the serializable definition of a command.
+type SchemaCommand struct {
+	// name of the sub-command ("" for the root command)
+	Name string `json:"name"`
+
+	// alternative names of the sub-command
+	Aliases []string `json:"aliases,omitempty"`
+
+	// description of the command
+	Description string `json:"description,omitempty"`
+
+	// deprecation message of the command
+	Deprecated string `json:"deprecated,omitempty"`
+
+	// name of the command replacing this deprecated command
+	ReplacedBy string `json:"replacedBy,omitempty"`
+
+	// if the command is omitted from the help and completion
+	Hidden bool `json:"hidden,omitempty"`
+
+	// placement of the flags and arguments by name, for example `require` (see `Ordering`)
+	Ordering Ordering `json:"ordering,omitempty"`
+
+	// if unregistered flags are collected (see `CommandConfig.SetAllowUnknownFlags`)
+	AllowUnknownFlags bool `json:"allowUnknownFlags,omitempty"`
+
+	// if the arguments can be passed by name (see `CommandConfig.SetNamedArgs`)
+	NamedArgs bool `json:"namedArgs,omitempty"`
+
+	// version printed by the version flags or the version command (see `CommandConfig.SetVersion`)
+	Version *VersionInfo `json:"version,omitempty"`
+
+	// if the command prints the version (see `Registry.RegisterVersion`)
+	VersionCommand bool `json:"versionCommand,omitempty"`
+
+	// command-line flags, sorted by name
+	Flags []*SchemaFlag `json:"flags,omitempty"`
+
+	// command-line arguments in registration order
+	Args []*SchemaArg `json:"args,omitempty"`
+}
+
+// SchemaFlag type holds the serializable definition of a flag.
+type SchemaFlag struct {
+	// long name of the flag (without `no-` prefix for an inverted flag)
+	Name string `json:"name"`
+
+	// short name of the flag
+	ShortName string `json:"shortName,omitempty"`
+
+	// description of the flag
+	Description string `json:"description,omitempty"`
+
+	// if the flag holds boolean value
+	IsBoolean bool `json:"isBoolean,omitempty"`
+
+	// if the flag is an inverted flag (with `--no-` prefix)
+	IsInverted bool `json:"isInverted,omitempty"`
+
+	// if the flag must be provided in the command-line arguments
+	Required bool `json:"required,omitempty"`
+
+	// if the flag holds a sensitive value
+	Sensitive bool `json:"sensitive,omitempty"`
+
+	// name of the environment variable providing the value of the flag
+	EnvVar string `json:"envVar,omitempty"`
+
+	// deprecation message of the flag
+	Deprecated string `json:"deprecated,omitempty"`
+
+	// name of the flag replacing this deprecated flag
+	ReplacedBy string `json:"replacedBy,omitempty"`
+
+	// if the flag is omitted from the help and completion
+	Hidden bool `json:"hidden,omitempty"`
+
+	// default value of the flag
+	DefaultValue string `json:"defaultValue"`
+
+	// list of all valid values that are accepted
+	ValidVals []string `json:"validVals,omitempty"`
+}
+
+// SchemaArg type holds the serializable definition of an argument.
+type SchemaArg struct {
+	// name of the argument (without `...` suffix)
+	Name string `json:"name"`
+
+	// description of the argument
+	Description string `json:"description,omitempty"`
+
+	// variadic argument can take multiple values
+	IsVariadic bool `json:"isVariadic,omitempty"`
+
+	// if the variadic argument takes as few values as possible (see `ArgCommand.SetNonGreedy`)
+	NonGreedy bool `json:"nonGreedy,omitempty"`
+
+	// if the argument must be provided in the command-line arguments
+	Required bool `json:"required,omitempty"`
+
+	// default value of the argument
+	DefaultValue string `json:"defaultValue"`
+
+	// list of all valid values that are accepted
+	ValidVals []string `json:"validVals,omitempty"`
+}
+
+/*---------------------*/
+
+// Schema method returns the definition of all registered commands.
+func (registry *Registry) Schema() *Schema {
+	schema := &Schema{
+		Version:           SchemaVersion,
+		Name:              registry.Name,
+		Ordering:          registry.Ordering,
+		SkipLeadingFlags:  registry.SkipLeadingFlags,
+		Overlap:           registry.Overlap,
+		AllowUnknownFlags: registry.AllowUnknownFlags,
+		DumpArgs:          registry.DumpArgs,
+		HelpFlags:         registry.HelpFlags,
+		ResponseFiles:     registry.ResponseFiles,
+		Plugins:           registry.Plugins,
+		Commands:          make([]*SchemaCommand, 0, len(registry.Commands)),
+	}
+
+	for _, name := range registry.CommandNames() {
+		commandConfig := registry.Commands[name]
+
+		command := &SchemaCommand{
+			Name:        commandConfig.Name,
+			Aliases:     append([]string(nil), commandConfig.Aliases...),
+			Description: commandConfig.Description,
+			Deprecated:  commandConfig.Deprecated,
+			ReplacedBy:  commandConfig.ReplacedBy,
+			Hidden:      commandConfig.Hidden,
+			Ordering:    commandConfig.Ordering,
+
+			AllowUnknownFlags: commandConfig.AllowUnknownFlags,
+			NamedArgs:         commandConfig.NamedArgs,
+			VersionCommand:    commandConfig.printsVersion,
+		}
+		if commandConfig.Version != nil {
+			version := *commandConfig.Version
+			command.Version = &version
+		}
+
+		for _, flagName := range commandConfig.FlagNames() {
+			flag := commandConfig.Flags[flagName]
+			command.Flags = append(command.Flags, &SchemaFlag{
+				Name:         flag.Name,
+				ShortName:    flag.ShortName,
+				Description:  flag.Description,
+				IsBoolean:    flag.IsBoolean,
+				IsInverted:   flag.IsInverted,
+				Required:     flag.Required,
+				Sensitive:    flag.Sensitive,
+				EnvVar:       flag.EnvVar,
+				Deprecated:   flag.Deprecated,
+				ReplacedBy:   flag.ReplacedBy,
+				Hidden:       flag.Hidden,
+				DefaultValue: flag.DefaultValue,
+				ValidVals:    flag.ValidValsList(),
+			})
+		}
+
+		for _, argName := range commandConfig.ArgNames {
+			arg := commandConfig.Args[argName]
+			command.Args = append(command.Args, &SchemaArg{
+				Name:         arg.Name,
+				Description:  arg.Description,
+				IsVariadic:   arg.IsVariadic,
+				NonGreedy:    arg.NonGreedy,
+				Required:     arg.Required,
+				DefaultValue: arg.DefaultValue,
+				ValidVals:    arg.ValidValsList(),
+			})
+		}
+
+		schema.Commands = append(schema.Commands, command)
+	}
+
+	return schema
+}
+
+// ExportSchema method serializes the definition of all registered commands to JSON.
+func (registry *Registry) ExportSchema() ([]byte, error) {
+	return json.MarshalIndent(registry.Schema(), "", "  ")
+}
+
+// NewRegistryFromSchema returns new instance of the "Registry" with commands defined by the schema.
+// If the schema version is not supported, it returns `ErrorSchemaVersion` error.
+// If the schema contains duplicated or conflicting definitions, it returns `ErrorSchemaInvalid` error.
+func NewRegistryFromSchema(schema *Schema) (*Registry, error) {
+	if schema.Version != SchemaVersion {
+		return nil, ErrorSchemaVersion{schema.Version}
+	}
+
+	if _, err := schema.Ordering.MarshalText(); err != nil {
+		return nil, ErrorSchemaInvalid{"", err.Error()}
+	}
+	if _, err := schema.Overlap.MarshalText(); err != nil {
+		return nil, ErrorSchemaInvalid{"", err.Error()}
+	}
+
+	registry := NewRegistry()
+	registry.Name = schema.Name
+	registry.Ordering = schema.Ordering
+	registry.SkipLeadingFlags = schema.SkipLeadingFlags
+	registry.Overlap = schema.Overlap
+	registry.AllowUnknownFlags = schema.AllowUnknownFlags
+	registry.DumpArgs = schema.DumpArgs
+	registry.HelpFlags = schema.HelpFlags
+	registry.ResponseFiles = schema.ResponseFiles
+	registry.Plugins = schema.Plugins
+
+	for _, command := range schema.Commands {
+		if command == nil {
+			return nil, ErrorSchemaInvalid{"", "null command"}
+		}
+
+		commandConfig, exist := registry.Register(command.Name)
+		if exist {
+			return nil, ErrorSchemaInvalid{command.Name, "command is already registered"}
+		}
+		commandConfig.SetDescription(command.Description).SetDeprecated(command.Deprecated).SetReplacedBy(command.ReplacedBy).SetHidden(command.Hidden)
+
+		if _, err := command.Ordering.MarshalText(); err != nil {
+			return nil, ErrorSchemaInvalid{command.Name, err.Error()}
+		}
+		commandConfig.SetOrdering(command.Ordering).SetAllowUnknownFlags(command.AllowUnknownFlags).SetNamedArgs(command.NamedArgs)
+
+		if command.VersionCommand && command.Version == nil {
+			return nil, ErrorSchemaInvalid{command.Name, "version command without a version"}
+		}
+		if command.Version != nil {
+			version := *command.Version
+			commandConfig.Version = &version
+			commandConfig.printsVersion = command.VersionCommand
+		}
+
+		for _, f := range command.Flags {
+			if f == nil || removeWhitespaces(f.Name) == "" {
+				return nil, ErrorSchemaInvalid{command.Name, "flag without a name"}
+			}
+
+			name := f.Name
+			if f.IsInverted {
+				if !f.IsBoolean {
+					return nil, ErrorSchemaInvalid{command.Name, fmt.Sprintf("inverted flag %s is not boolean", f.Name)}
+				}
+				name = "no-" + name
+			}
+
+			flag, exist := commandConfig.AddFlagWithValid(name, f.ShortName, f.IsBoolean, f.DefaultValue, f.ValidVals)
+			if exist {
+				return nil, ErrorSchemaInvalid{command.Name, fmt.Sprintf("flag %s is already registered", f.Name)}
+			}
+			flag.SetDescription(f.Description).SetRequired(f.Required).SetSensitive(f.Sensitive)
+			flag.SetEnvVar(f.EnvVar).SetDeprecated(f.Deprecated).SetReplacedBy(f.ReplacedBy).SetHidden(f.Hidden)
+		}
+
+		for _, a := range command.Args {
+			if a == nil || removeWhitespaces(a.Name) == "" {
+				return nil, ErrorSchemaInvalid{command.Name, "argument without a name"}
+			}
+
+			name := a.Name
+			if a.IsVariadic {
+				name += "..."
+			}
+
+			arg, exist := commandConfig.AddArgWithValid(name, a.DefaultValue, a.ValidVals)
+			if exist {
+				return nil, ErrorSchemaInvalid{command.Name, fmt.Sprintf("argument %s is already registered", a.Name)}
+			}
+			arg.SetDescription(a.Description).SetRequired(a.Required).SetNonGreedy(a.NonGreedy)
+		}
+	}
+
+	// aliases are registered after all commands, so an alias can't shadow a command defined later
+	for _, command := range schema.Commands {
+		for _, alias := range command.Aliases {
+			if _, exist := registry.RegisterAlias(command.Name, alias); exist {
+				return nil, ErrorSchemaInvalid{command.Name, fmt.Sprintf("alias %s is already registered", alias)}
+			}
+		}
+	}
+
+	return registry, nil
+}
+
+// ImportSchema returns new instance of the "Registry" with commands defined by the JSON schema document.
+// See `NewRegistryFromSchema` for the returned errors.
+func ImportSchema(data []byte) (*Registry, error) {
+	schema := &Schema{}
+	if err := json.Unmarshal(data, schema); err != nil {
+		return nil, err
+	}
+
+	return NewRegistryFromSchema(schema)
+}
@@ -0,0 +1,216 @@
+package clapper
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// registry used by the schema tests
+func schemaTestRegistry() *Registry {
+	registry := NewRegistry()
+
+	rootCommand, _ := registry.Register("")
+	rootCommand.SetDescription("manage users")
+	output, _ := rootCommand.AddArg("output", "")
+	output.SetDescription("output file")
+	rootCommand.AddFlag("force", "f", true, "")
+	rootCommand.AddFlag("dir", "", false, "/var/users")
+	rootCommand.SetVersion("1.2.0")
+	registry.RegisterVersion("1.2.0")
+
+	infoCommand, _ := registry.Register("info")
+	infoCommand.AddArgWithValid("category", "manager", []string{"manager", "student"})
+	infoCommand.AddArg("subjects...", "")
+	infoCommand.AddFlagWithValid("version", "V", false, "1.0.1", []string{"2.0.0", "1.0.1"})
+	noOutput, _ := infoCommand.AddFlag("no-output", "", true, "")
+	noOutput.SetDescription("do not write output")
+	registry.RegisterAlias("info", "i")
+
+	ghostCommand, _ := registry.Register("ghost")
+	args, _ := ghostCommand.AddArg("args...", "")
+	args.SetNonGreedy(true)
+	ghostCommand.AddArg("last", "")
+	ghostCommand.SetOrdering(OrderRequire).SetAllowUnknownFlags(true).SetNamedArgs(true)
+
+	return registry
+}
+
+// test the exported schema
+func TestExportSchema(t *testing.T) {
+	schema := schemaTestRegistry().Schema()
+
+	if schema.Version != SchemaVersion {
+		t.Fatalf("got version %d, want %d", schema.Version, SchemaVersion)
+	}
+	if len(schema.Commands) != 4 || schema.Commands[0].Name != "" || schema.Commands[2].Name != "info" {
+		t.Fatalf("got commands %#v", schema.Commands)
+	}
+
+	info := schema.Commands[2]
+	if !reflect.DeepEqual(info.Aliases, []string{"i"}) {
+		t.Errorf("got aliases %q", info.Aliases)
+	}
+
+	wantFlags := []*SchemaFlag{
+		{Name: "output", Description: "do not write output", IsBoolean: true, IsInverted: true, DefaultValue: "true"},
+		{Name: "version", ShortName: "V", DefaultValue: "1.0.1", ValidVals: []string{"2.0.0", "1.0.1"}},
+	}
+	if !reflect.DeepEqual(info.Flags, wantFlags) {
+		t.Errorf("got flags %+v, want %+v", info.Flags, wantFlags)
+	}
+
+	wantArgs := []*SchemaArg{
+		{Name: "category", DefaultValue: "manager", ValidVals: []string{"manager", "student"}},
+		{Name: "subjects", IsVariadic: true},
+	}
+	if !reflect.DeepEqual(info.Args, wantArgs) {
+		t.Errorf("got args %+v, want %+v", info.Args, wantArgs)
+	}
+}
+
+// test export and import round trip
+func TestImportSchemaRoundTrip(t *testing.T) {
+	data, err := schemaTestRegistry().ExportSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry, err := ImportSchema(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data2, err := registry.ExportSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(data2) {
+		t.Fatalf("got\n%s\nwant\n%s", data2, data)
+	}
+
+	command, err := registry.Parse([]string{"i", "student", "--no-output", "-V", "2.0.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if command.Name != "info" || command.Flags["output"].Value != "false" || command.Flags["version"].Value != "2.0.0" {
+		t.Fatalf("got %#v", command)
+	}
+
+	// the options of the commands are imported
+	command, err = registry.Parse([]string{"ghost", "--x=1", "--last", "z", "a", "-v"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := command.Args["args"].Value; got != "a,-v" {
+		t.Errorf("got args %q, want %q", got, "a,-v")
+	}
+	if got := command.Args["last"].Value; got != "z" {
+		t.Errorf("got last argument %q, want %q", got, "z")
+	}
+	if !reflect.DeepEqual(command.Unknown, []string{"--x=1"}) {
+		t.Errorf("got unknown flags %q", command.Unknown)
+	}
+
+	for _, args := range [][]string{{"--version"}, {"version"}} {
+		var stdout strings.Builder
+		registry.Stdout = &stdout
+		if _, err := registry.Parse(args); err != (ErrorVersion{}) || !strings.Contains(stdout.String(), " 1.2.0 (") {
+			t.Errorf("%q: got %q (%v)", args, stdout.String(), err)
+		}
+	}
+
+	command, err = registry.Parse([]string{"ghost", "a", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := command.Args["args"].Value + ";" + command.Args["last"].Value; got != "a;b" {
+		t.Errorf("got args %q, want %q", got, "a;b")
+	}
+}
+
+// test import errors
+func TestImportSchemaErrors(t *testing.T) {
+	tests := map[string]struct {
+		doc  string
+		want error
+	}{
+		"version": {
+			`{"version": 2, "commands": []}`,
+			ErrorSchemaVersion{2},
+		},
+		"duplicated command": {
+			`{"version": 1, "commands": [{"name": "info"}, {"name": "info"}]}`,
+			ErrorSchemaInvalid{"info", "command is already registered"},
+		},
+		"inverted flag": {
+			`{"version": 1, "commands": [{"name": "info", "flags": [{"name": "clean", "isInverted": true}]}]}`,
+			ErrorSchemaInvalid{"info", "inverted flag clean is not boolean"},
+		},
+		"version command": {
+			`{"version": 1, "commands": [{"name": "version", "versionCommand": true}]}`,
+			ErrorSchemaInvalid{"version", "version command without a version"},
+		},
+		"alias": {
+			`{"version": 1, "commands": [{"name": "info", "aliases": ["ghost"]}, {"name": "ghost"}]}`,
+			ErrorSchemaInvalid{"info", "alias ghost is already registered"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ImportSchema([]byte(tt.doc))
+			if err != tt.want {
+				t.Fatalf("got %#v, want %#v", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := ImportSchema([]byte(`{`)); err == nil || !strings.Contains(err.Error(), "unexpected end") {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := ImportSchema([]byte(`{"version": 1, "commands": [{"name": "info", "ordering": "sideways"}]}`)); err == nil ||
+		!strings.Contains(err.Error(), `invalid ordering "sideways"`) {
+		t.Fatalf("got %v", err)
+	}
+
+	schema := &Schema{Version: SchemaVersion, Commands: []*SchemaCommand{{Name: "info", Ordering: 9}}}
+	if _, err := NewRegistryFromSchema(schema); err != (ErrorSchemaInvalid{"info", "invalid ordering 9"}) {
+		t.Fatalf("got %#v", err)
+	}
+	schema = &Schema{Version: SchemaVersion, Overlap: 5}
+	if _, err := NewRegistryFromSchema(schema); err != (ErrorSchemaInvalid{"", "invalid overlap policy 5"}) {
+		t.Fatalf("got %#v", err)
+	}
+}
+
+// test the options of the registry in the schema
+func TestRegistrySchema(t *testing.T) {
+	registry := NewRegistry()
+	registry.Name = "app"
+	registry.Ordering = OrderFlagsBeforeCommand
+	registry.Overlap = OverlapError
+	registry.SkipLeadingFlags = true
+	registry.HelpFlags = true
+	command, _ := registry.Register("info")
+	command.SetOrdering(OrderRequire)
+
+	data, err := registry.ExportSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"name": "app"`, `"ordering": "flags-before-command"`, `"overlap": "error"`, `"ordering": "require"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("schema %s doesn't contain %s", data, want)
+		}
+	}
+
+	imported, err := ImportSchema(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.Name != "app" || imported.Ordering != OrderFlagsBeforeCommand || imported.Overlap != OverlapError ||
+		!imported.SkipLeadingFlags || !imported.HelpFlags || imported.Commands["info"].Ordering != OrderRequire {
+		t.Errorf("got registry %+v", imported)
+	}
+}
@@ -0,0 +1,159 @@
+package clapper
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// RedactedValue replaces a non-empty value of a sensitive flag in the formatted output.
+const RedactedValue = "<redacted>"
+
+// SensitiveFileSuffix is the suffix of the flag reading the value of a sensitive flag from a file.
+const SensitiveFileSuffix = "-file"
+
+// ErrorSensitiveFile represents an error when the value of a sensitive flag can't be read from a file.
+type ErrorSensitiveFile struct {
+	Name string
+	File string
+	Err  error
+}
+
+func (e ErrorSensitiveFile) Error() string {
+	return fmt.Sprintf("can't read value of flag %s from file %s: %v", e.Name, e.File, e.Err)
+}
+
+// Unwrap returns the cause of the error.
+func (e ErrorSensitiveFile) Unwrap() error {
+	return e.Err
+}
+
+/*---------------------*/
+
+// return the value or `RedactedValue` for a non-empty value of a sensitive flag
+func (f *FlagCommand) redact(v string) string {
+	if f.Sensitive && v != "" {
+		return RedactedValue
+	}
+	return v
+}
+
+// check if the value of the flag can be read from a file
+func (f *FlagCommand) sensitiveFile() bool {
+	return f.Sensitive && !f.IsBoolean
+}
+
+// return the sensitive flag for the `<flag>-file` name (if it is not registered as a flag itself)
+func (commandConfig *CommandConfig) sensitiveFileFlag(name string) *FlagCommand {
+	if !strings.HasSuffix(name, SensitiveFileSuffix) {
+		return nil
+	}
+
+	flag, ok := commandConfig.Flags[strings.TrimSuffix(name, SensitiveFileSuffix)]
+	if !ok || !flag.sensitiveFile() {
+		return nil
+	}
+
+	return flag
+}
+
+// read the value of a sensitive flag from the file (or from the standard input if path is `-`)
+// the trailing line ending is removed
+func (registry *Registry) readSensitiveFile(flag *FlagCommand, path string) (string, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	if path == "-" {
+		data, err = ioutil.ReadAll(registry.stdin())
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return "", ErrorSensitiveFile{flag.Name, path, err}
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+/*---------------------*/
+
+// String method returns the value of the flag (`RedactedValue` for a non-empty value of a sensitive flag).
+func (flag Flag) String() string {
+	if flag.Sensitive && flag.Value != "" {
+		return RedactedValue
+	}
+	return flag.Value
+}
+
+// GoString method returns the Go syntax representation of the flag (used by `%#v`) with a redacted sensitive value.
+func (flag Flag) GoString() string {
+	return fmt.Sprintf("clapper.Flag{Name:%q, IsBoolean:%t, Sensitive:%t, Value:%q, Source:%q, SourceName:%q}",
+		flag.Name, flag.IsBoolean, flag.Sensitive, flag.String(), flag.Source, flag.SourceName)
+}
+
+// String method returns the parsed command in the form `<command> <arg>=<value> ... --<flag>=<value> ... -- <passthrough>...`,
+// arguments are in registration order (see `CommandParsed.ArgNames`), flags are sorted by name and sensitive values are redacted.
+func (commandParsed *CommandParsed) String() string {
+	var b strings.Builder
+
+	b.WriteString(commandParsed.Name)
+
+	write := func(format string, a ...interface{}) {
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(&b, format, a...)
+	}
+
+	argNames := make([]string, 0, len(commandParsed.Args))
+	ordered := make(map[string]bool, len(commandParsed.ArgNames))
+	for _, name := range commandParsed.ArgNames {
+		if _, ok := commandParsed.Args[name]; ok && !ordered[name] {
+			ordered[name] = true
+			argNames = append(argNames, name)
+		}
+	}
+	extra := make([]string, 0)
+	for name := range commandParsed.Args {
+		if !ordered[name] {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	argNames = append(argNames, extra...)
+	for _, name := range argNames {
+		write("%s=%q", name, commandParsed.Args[name].Value)
+	}
+
+	flagNames := make([]string, 0, len(commandParsed.Flags))
+	for name := range commandParsed.Flags {
+		flagNames = append(flagNames, name)
+	}
+	sort.Strings(flagNames)
+	for _, name := range flagNames {
+		write("--%s=%q", name, commandParsed.Flags[name].String())
+	}
+
+	if len(commandParsed.Unknown) > 0 {
+		write("unknown=%q", commandParsed.Unknown)
+	}
+
+	if len(commandParsed.Passthrough) > 0 {
+		write("-- %q", commandParsed.Passthrough)
+	}
+
+	return b.String()
+}
+
+// GoString method returns the same representation as `String` (used by `%#v`).
+func (commandParsed *CommandParsed) GoString() string {
+	return commandParsed.String()
+}
+
+// return the help row of the file flag of a sensitive flag
+func sensitiveFileRow(flag *FlagCommand, o output, l localizer) row {
+	return row{"      --" + flag.Name + SensitiveFileSuffix + " <path>", o.theme.Flag, l.format("help.sensitiveFile", flag.Name)}
+}
@@ -0,0 +1,102 @@
+package clapper_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/msaf1980/clapper"
+	"github.com/msaf1980/clapper/clappertest"
+)
+
+// test redaction of the sensitive values
+func TestSensitiveRedaction(t *testing.T) {
+	registry := newDemoRegistry(true)
+	token, _ := registry.Commands[""].AddFlagWithValid("token", "t", false, "default-secret", []string{"default-secret", "s3cr3t"})
+	token.SetSensitive(true).SetDescription("access token")
+
+	command := clappertest.Parse(t, registry, "--dir", "me", "--token", "s3cr3t")
+	clappertest.AssertFlags(t, command, map[string]string{"token": "s3cr3t"})
+
+	for _, formatted := range []string{
+		fmt.Sprintf("%v", command),
+		fmt.Sprintf("%s", command),
+		fmt.Sprintf("%#v", command),
+		fmt.Sprintf("%v", command.Flags["token"]),
+		fmt.Sprintf("%#v", command.Flags["token"]),
+		fmt.Sprintf("%#v", *command.Flags["token"]),
+		clappertest.Help(t, registry, ""),
+	} {
+		if strings.Contains(formatted, "secret") || strings.Contains(formatted, "s3cr3t") {
+			t.Errorf("sensitive value is not redacted in %q", formatted)
+		}
+	}
+
+	if got, want := command.String(), `output="" --dir="me" --force="false" --token="<redacted>" --verbose="false" --version=""`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	data, err := json.Marshal(command)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "s3cr3t") || !strings.Contains(string(data), `"sensitive":true`) {
+		t.Errorf("got %s", data)
+	}
+
+	result := clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"--token", "typo"}})
+	clappertest.AssertError(t, result.Err, clapper.ErrorUnsupportedValue{Name: "token", Value: clapper.RedactedValue})
+	if strings.Contains(result.Err.Error(), "typo") {
+		t.Errorf("sensitive value is not redacted in %q", result.Err)
+	}
+
+	// the error of the bound value can contain the value
+	var pin int
+	registry.Commands[""].Flags["token"].SetFlagValue(intValue{&pin})
+	result = clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"--token", "s3cr3t"}})
+	var valueErr clapper.ErrorFlagValue
+	if !errors.As(result.Err, &valueErr) || !valueErr.Sensitive || valueErr.Value != clapper.RedactedValue {
+		t.Fatalf("got %#v", result.Err)
+	}
+	for _, message := range []string{result.Err.Error(), registry.ErrorMessage(result.Err)} {
+		if strings.Contains(message, "s3cr3t") {
+			t.Errorf("sensitive value is not redacted in %q", message)
+		}
+	}
+}
+
+// test reading sensitive values from a file or the standard input
+func TestSensitiveFile(t *testing.T) {
+	registry := newDemoRegistry(true)
+	token, _ := registry.Commands[""].AddFlagWithValid("token", "t", false, "default-secret", []string{"default-secret", "s3cr3t"})
+	token.SetSensitive(true).SetDescription("access token")
+
+	result := clappertest.Run(t, registry, clappertest.Fixture{
+		Args:  []string{"--token-file", clappertest.DirPlaceholder + "/token"},
+		Files: map[string]string{"token": "s3cr3t\n"},
+	})
+	if result.Err != nil {
+		t.Fatal(result.Err)
+	}
+	clappertest.AssertFlags(t, result.Command, map[string]string{"token": "s3cr3t"})
+	if source := result.Command.Flags["token"].Source; source != clapper.SourceFile {
+		t.Errorf("got source %q", source)
+	}
+
+	registry.Stdin = strings.NewReader("s3cr3t\r\n")
+	command := clappertest.Parse(t, registry, "--token-file", "-")
+	clappertest.AssertFlags(t, command, map[string]string{"token": "s3cr3t"})
+
+	result = clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"--token-file", "/nonexistent/token"}})
+	var fileErr clapper.ErrorSensitiveFile
+	if !errors.As(result.Err, &fileErr) || fileErr.Name != "token" || !os.IsNotExist(fileErr.Err) {
+		t.Errorf("got %#v", result.Err)
+	}
+
+	// only sensitive flags can be read from a file
+	result = clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"--dir-file", "-"}})
+	clappertest.AssertError(t, result.Err, clapper.ErrorUnknownFlag{Name: "--dir-file"})
+}
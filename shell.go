@@ -0,0 +1,364 @@
+package clapper
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// DefaultHistorySize is the number of lines kept in the shell history if `Shell.HistorySize` is 0.
+const DefaultHistorySize = 500
+
+// Shell type runs the registered commands interactively: it reads a line, splits it (see `Split`),
+// parses it (see `Registry.Parse`) and calls the handler with the parsed command (or runs a plugin command,
+// see `Registry.RunPlugin`). Errors are written to
+// `Registry.Stderr` and the shell continues with the next line.
+//
+// On a terminal, the line can be edited: Tab completes command names, flag names and valid values
+// (see `Registry.Complete`), Up and Down keys recall the history, Ctrl-C cancels the line and Ctrl-D exits.
+// Built-in commands `help [command]`, `history` and `exit` (or `quit`) are available
+// unless commands with the same names are registered.
+type Shell struct {
+	// registry of the commands
+	Registry *Registry
+
+	// function called with each parsed command, its error is written to `Registry.Stderr`
+	Handler func(command *CommandParsed) error
+
+	// prompt written before each line (`<Registry.Name>> ` if empty)
+	Prompt string
+
+	// reader of the lines (`os.Stdin` if nil, the lines are edited only if the standard input is a terminal)
+	In io.Reader
+
+	// if not empty, the history is read from the file at start and the file is rewritten with the history
+	// after each line, so it keeps the last `HistorySize` lines (a warning is written to `Registry.Stderr`
+	// if the file can't be written)
+	HistoryFile string
+
+	// maximum number of the lines in the history (`DefaultHistorySize` if 0)
+	HistorySize int
+
+	// entered lines (the oldest first), the values of the sensitive flags are redacted (see `FlagCommand.SetSensitive`)
+	History []string
+}
+
+// NewShell returns a new shell running the commands of the registry with the handler.
+func NewShell(registry *Registry, handler func(command *CommandParsed) error) *Shell {
+	return &Shell{
+		Registry: registry,
+		Handler:  handler,
+	}
+}
+
+// return the prompt of the shell
+func (shell *Shell) prompt() string {
+	if shell.Prompt != "" {
+		return shell.Prompt
+	}
+	return shell.Registry.programName() + "> "
+}
+
+// check if the built-in command is available
+func (shell *Shell) isBuiltin(name string) bool {
+	if _, ok := shell.Registry.Commands[name]; ok {
+		return false
+	}
+
+	switch name {
+	case "help", "history", "exit", "quit":
+		return true
+	}
+
+	return false
+}
+
+// Run method reads and executes the lines until `exit` built-in command or the end of the input.
+// It returns an error only if the input can't be read.
+func (shell *Shell) Run() error {
+	if err := shell.readHistory(); err != nil {
+		return err
+	}
+
+	// read the lines
+	var readLine func() (string, error)
+	if shell.In == nil && isTerminal(os.Stdin) {
+		editor := &lineEditor{
+			in:       bufio.NewReader(os.Stdin),
+			out:      os.Stdout,
+			prompt:   shell.prompt(),
+			complete: shell.Complete,
+		}
+		readLine = func() (string, error) {
+			// the terminal is restored while the command runs
+			restore, err := enableRawMode(os.Stdin)
+			if err != nil {
+				return "", err
+			}
+			defer restore()
+
+			editor.history = shell.History
+			return editor.readLine()
+		}
+	} else {
+		in := shell.In
+		if in == nil {
+			in = os.Stdin
+		}
+		reader := bufio.NewReader(in)
+		readLine = func() (string, error) {
+			fmt.Fprint(shell.Registry.stdout(), shell.prompt())
+			line, err := reader.ReadString('\n')
+			if err == io.EOF && line != "" {
+				err = nil
+			}
+			return strings.TrimRight(line, "\r\n"), err
+		}
+	}
+
+	for {
+		line, err := readLine()
+		if err == errInterrupt {
+			continue
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		exit, err := shell.Execute(line)
+		if err != nil {
+			o := shell.Registry.output(shell.Registry.stderr())
+			fmt.Fprintln(shell.Registry.stderr(), o.paint(o.theme.Error, shell.Registry.messages().format("shell.error", shell.Registry.ErrorMessage(err))))
+		}
+		if exit {
+			return nil
+		}
+	}
+}
+
+// Execute method executes a line: a built-in command or a registered command passed to the handler.
+// The first return value is `true` if the shell should exit.
+func (shell *Shell) Execute(line string) (bool, error) {
+	values, err := Split(line)
+	if err != nil || len(values) == 0 {
+		return false, err
+	}
+
+	// a history file which can't be written doesn't prevent the execution
+	if err := shell.addHistory(shell.historyLine(line, values)); err != nil {
+		o := shell.Registry.output(shell.Registry.stderr())
+		warning := shell.Registry.messages().format("shell.historyFile", shell.HistoryFile, err)
+		fmt.Fprintln(shell.Registry.stderr(), o.paint(o.theme.Warning, shell.Registry.messages().format("warning", warning)))
+	}
+
+	if shell.isBuiltin(values[0]) {
+		switch values[0] {
+		case "exit", "quit":
+			return true, nil
+		case "history":
+			for i, line := range shell.History {
+				fmt.Fprintf(shell.Registry.stdout(), "%5d  %s\n", i+1, line)
+			}
+			return false, nil
+		case "help":
+			if len(values) > 1 {
+				return false, shell.Registry.WriteHelp(shell.Registry.stdout(), values[1])
+			}
+			return false, shell.writeHelp(shell.Registry.stdout())
+		}
+	}
+
+	command, err := shell.Registry.Parse(values)
+	switch err.(type) {
+	case ErrorDumpArgs, ErrorHelp, ErrorVersion:
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if command.Plugin != "" {
+		return false, shell.Registry.RunPlugin(command)
+	}
+	if shell.Handler == nil {
+		return false, nil
+	}
+	return false, shell.Handler(command)
+}
+
+// Complete method returns the candidates completing the value after the values of a line (see `Registry.Complete`),
+// including the built-in commands.
+func (shell *Shell) Complete(values []string, toComplete string) []string {
+	candidates := shell.Registry.Complete(values, toComplete)
+
+	if len(values) == 0 {
+		for _, name := range []string{"help", "history", "exit", "quit"} {
+			if shell.isBuiltin(name) {
+				candidates = append(candidates, name)
+			}
+		}
+	} else if len(values) == 1 && values[0] == "help" && shell.isBuiltin("help") {
+		candidates = append(candidates, shell.Registry.Complete(nil, toComplete)...)
+	}
+
+	return filterCandidates(candidates, toComplete)
+}
+
+// write the help of the shell
+func (shell *Shell) writeHelp(w io.Writer) error {
+	var b bytes.Buffer
+	o := shell.Registry.output(w)
+	l := shell.Registry.messages()
+
+	names, plugins := shell.Registry.visibleCommandNames(), shell.Registry.PluginNames()
+	if len(names) > 0 || len(plugins) > 0 {
+		fmt.Fprintln(&b, o.paint(o.theme.Heading, l.format("help.commandsHeading")))
+		rows := make([]row, 0, len(names)+len(plugins))
+		for _, name := range names {
+			if name != "" {
+				rows = append(rows, row{"  " + name, o.theme.Command, l.text(shell.Registry.Commands[name].Description)})
+			}
+		}
+		for _, name := range plugins {
+			rows = append(rows, row{"  " + name, o.theme.Command, l.format("help.plugin", shell.Registry.programName()+"-"+name)})
+		}
+		o.writeTable(&b, rows)
+		fmt.Fprintln(&b)
+	}
+
+	fmt.Fprintln(&b, o.paint(o.theme.Heading, l.format("shell.builtinsHeading")))
+	rows := make([]row, 0, 3)
+	if shell.isBuiltin("help") {
+		rows = append(rows, row{"  help " + l.format("help.command"), o.theme.Command, l.format("shell.help")})
+	}
+	if shell.isBuiltin("history") {
+		rows = append(rows, row{"  history", o.theme.Command, l.format("shell.history")})
+	}
+	if shell.isBuiltin("exit") {
+		rows = append(rows, row{"  exit", o.theme.Command, l.format("shell.exit")})
+	}
+	o.writeTable(&b, rows)
+
+	lines := strings.Split(b.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+
+	_, err := io.WriteString(w, strings.Join(lines, "\n"))
+	return err
+}
+
+/*---------------------*/
+
+// return the maximum number of the lines in the history
+func (shell *Shell) historySize() int {
+	if shell.HistorySize > 0 {
+		return shell.HistorySize
+	}
+	return DefaultHistorySize
+}
+
+// read the history file
+func (shell *Shell) readHistory() error {
+	if shell.HistoryFile == "" {
+		return nil
+	}
+
+	f, err := os.Open(shell.HistoryFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			shell.History = append(shell.History, line)
+		}
+	}
+	if len(shell.History) > shell.historySize() {
+		shell.History = shell.History[len(shell.History)-shell.historySize():]
+	}
+
+	return scanner.Err()
+}
+
+// return the line added to the history, the values of the sensitive flags are replaced with `RedactedValue`
+func (shell *Shell) historyLine(line string, values []string) string {
+	commandName, commandAt, err := shell.Registry.resolveCommand(values)
+	commandConfig, ok := shell.Registry.Commands[commandName]
+	if err != nil || !ok {
+		return line
+	}
+	rootCommandConfig := shell.Registry.Commands[""]
+
+	redacted := append(make([]string, 0, len(values)), values...)
+	found := false
+	for i := 0; i < len(redacted); i++ {
+		value := redacted[i]
+		if isEndOfFlags(value) {
+			break
+		}
+		if !isFlag(value) {
+			continue
+		}
+
+		// the leading flags are the global flags of the root command
+		flagConfig := commandConfig
+		if i < commandAt && shell.Registry.SkipLeadingFlags {
+			flagConfig = rootCommandConfig
+		}
+		name := strings.SplitN(value, "=", 2)[0]
+		flag := flagConfig.lookupFlagValue(shell.Registry.canonicalFlag(flagConfig, name))
+		if flag == nil || !flag.Sensitive || flag.IsBoolean {
+			continue
+		}
+
+		found = true
+		if name != value {
+			redacted[i] = name + "=" + RedactedValue
+		} else if i+1 < len(redacted) && !isFlag(redacted[i+1]) {
+			i++
+			redacted[i] = RedactedValue
+		}
+	}
+
+	if !found {
+		return line
+	}
+	return Join(redacted)
+}
+
+// add the line to the history unless it repeats the last line and save the history to the history file
+func (shell *Shell) addHistory(line string) error {
+	if strings.ContainsAny(line, "\r\n") || (len(shell.History) > 0 && shell.History[len(shell.History)-1] == line) {
+		return nil
+	}
+
+	shell.History = append(shell.History, line)
+	if len(shell.History) > shell.historySize() {
+		shell.History = shell.History[len(shell.History)-shell.historySize():]
+	}
+
+	if shell.HistoryFile == "" {
+		return nil
+	}
+
+	// the file is rewritten instead of appended, so it doesn't grow beyond the size of the history
+	var buf bytes.Buffer
+	for _, line := range shell.History {
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	return ioutil.WriteFile(shell.HistoryFile, buf.Bytes(), 0600)
+}
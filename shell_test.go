@@ -0,0 +1,139 @@
+package clapper_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/msaf1980/clapper"
+)
+
+// test running commands in the shell
+func TestShell(t *testing.T) {
+	registry := newDemoRegistry(false)
+	registry.Commands["info"].SetDescription("show information")
+	var stdout, stderr bytes.Buffer
+	registry.Stdout, registry.Stderr = &stdout, &stderr
+
+	commands := make([]string, 0)
+	shell := clapper.NewShell(registry, func(command *clapper.CommandParsed) error {
+		commands = append(commands, command.String())
+		if command.Name == "ghost" {
+			return errors.New("boo")
+		}
+		return nil
+	})
+	shell.Prompt = "> "
+	shell.In = strings.NewReader("info student -o 'my dir'\n\nunknown\ninfo 'x\nghost\nhelp\nhelp ghost\nhistory\nexit\ninfo\n")
+
+	if err := shell.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	wantCommands := []string{
+		`info category="student" username="" subjects="" --clean="true" --output="my dir" --verbose="false" --version="1.0.1"`,
+		`ghost`,
+	}
+	if !reflect.DeepEqual(commands, wantCommands) {
+		t.Errorf("got commands\n%q\nwant\n%q", commands, wantCommands)
+	}
+
+	wantErrors := "error: unknown command unknown found in the arguments\n" +
+		"error: unterminated single quote at line 1, column 6\n" +
+		"error: boo\n"
+	if stderr.String() != wantErrors {
+		t.Errorf("got errors\n%s", stderr.String())
+	}
+
+	wantOutput := strings.Repeat("> ", 6) +
+		"Commands:\n  ghost\n  info    show information\n\n" +
+		"Built-in commands:\n  help [command]   show the help of the shell or a command\n  history          show the entered lines\n  exit             exit the shell\n" +
+		"> Usage: demo ghost\n" +
+		"> " +
+		"    1  info student -o 'my dir'\n    2  unknown\n    3  ghost\n    4  help\n    5  help ghost\n    6  history\n" +
+		"> "
+	if stdout.String() != wantOutput {
+		t.Errorf("got output\n%s\nwant\n%s", stdout.String(), wantOutput)
+	}
+}
+
+// test the completion and history file of the shell
+func TestShellHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clapper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	registry := newDemoRegistry(false)
+	registry.Stdout = ioutil.Discard
+	registry.Register("help")
+
+	path := filepath.Join(dir, "history")
+	if err := ioutil.WriteFile(path, []byte("info\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	shell := clapper.NewShell(registry, nil)
+	shell.HistoryFile = path
+	shell.HistorySize = 2
+	shell.In = strings.NewReader("ghost\nghost\nhelp\n")
+	if err := shell.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"ghost", "help"}; !reflect.DeepEqual(shell.History, want) {
+		t.Errorf("got history %q, want %q", shell.History, want)
+	}
+	// the history file keeps the last `HistorySize` lines
+	if data, _ := ioutil.ReadFile(path); string(data) != "ghost\nhelp\n" {
+		t.Errorf("got history file %q", data)
+	}
+
+	// the values of the sensitive flags are redacted
+	token, _ := registry.Commands["info"].AddFlag("token", "t", false, "")
+	token.SetSensitive(true)
+	shell.History = nil
+	for _, line := range []string{"info --token s3cr3t -v", "info -t=s3cr3t math"} {
+		if _, err := shell.Execute(line); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []string{"info --token '<redacted>' -v", "info '-t=<redacted>' math"}
+	if !reflect.DeepEqual(shell.History, want) {
+		t.Errorf("got history %q, want %q", shell.History, want)
+	}
+	if data, _ := ioutil.ReadFile(path); strings.Contains(string(data), "s3cr3t") {
+		t.Errorf("got history file %q", data)
+	}
+
+	// a history file which can't be written
+	var stderr bytes.Buffer
+	registry.Stderr = &stderr
+	executed := false
+	shell.Handler = func(command *clapper.CommandParsed) error {
+		executed = true
+		return nil
+	}
+	shell.HistoryFile = dir
+	if _, err := shell.Execute("ghost"); err != nil || !executed {
+		t.Errorf("line is not executed (%v)", err)
+	}
+	if !strings.HasPrefix(stderr.String(), "warning: can't write the history file ") {
+		t.Errorf("got stderr %q", stderr.String())
+	}
+	shell.Handler = nil
+
+	// the registered `help` command replaces the built-in command
+	if got, want := shell.Complete(nil, "h"), []string{"help", "history"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := shell.Complete([]string{"help"}, ""), []string{}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,203 @@
+package clapper
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ValueSource type describes where the value of a parsed flag or argument comes from.
+type ValueSource string
+
+const (
+	// SourceDefault means that the value is the registered default value
+	SourceDefault ValueSource = "default"
+
+	// SourceArgv means that the value is provided in the command-line arguments
+	SourceArgv ValueSource = "argv"
+
+	// SourceEnv means that the value is read from an environment variable (see `FlagCommand.SetEnvVar`)
+	SourceEnv ValueSource = "env"
+
+	// SourceConfig means that the value is read from a configuration (see `Registry.Configs`)
+	SourceConfig ValueSource = "config"
+
+	// SourceFile means that the value is read from a file (see `FlagCommand.SetSensitive`)
+	SourceFile ValueSource = "file"
+
+	// SourcePrompt means that the value is entered interactively (see `Prompter`)
+	SourcePrompt ValueSource = "prompt"
+)
+
+// ConfigSource interface provides flag values from a configuration, for example a configuration file.
+type ConfigSource interface {
+
+	// LookupFlag returns the value of the flag (long name without `--` prefix) of the command ("" for the root command)
+	// and the key of the value in the configuration.
+	LookupFlag(command string, flag string) (value string, key string, ok bool)
+
+	// String returns the name of the configuration, for example the path of the file.
+	String() string
+}
+
+// ErrorConfigFile represents an error when a configuration file has an invalid line.
+type ErrorConfigFile struct {
+	File string
+	Line int
+}
+
+func (e ErrorConfigFile) Error() string {
+	return fmt.Sprintf("invalid line %d in configuration file %s", e.Line, e.File)
+}
+
+/*---------------------*/
+
+// ConfigMap type is a `ConfigSource` holding the flag values in a map.
+// The value of a flag is looked up by the `<command>.<flag>` key and then by the `<flag>` key (shared by all commands).
+type ConfigMap struct {
+	// name of the configuration
+	Name string
+
+	// values by keys
+	Values map[string]string
+}
+
+// LookupFlag method implements `ConfigSource` interface.
+func (config *ConfigMap) LookupFlag(command string, flag string) (string, string, bool) {
+	if command != "" {
+		key := command + "." + flag
+		if value, ok := config.Values[key]; ok {
+			return value, key, true
+		}
+	}
+
+	value, ok := config.Values[flag]
+	return value, flag, ok
+}
+
+// String method implements `ConfigSource` interface.
+func (config *ConfigMap) String() string {
+	return config.Name
+}
+
+// ReadConfigFile reads a configuration file with `<flag> = <value>` lines.
+// Lines after a `[<command>]` line are the flags of that command (the `<command>.<flag>` keys).
+// Empty lines and lines starting with `#` or `;` are ignored, values can be quoted with `"` or `'`.
+func ReadConfigFile(path string) (*ConfigMap, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	config := &ConfigMap{
+		Name:   path,
+		Values: make(map[string]string),
+	}
+
+	section := ""
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || key == "" {
+			return nil, ErrorConfigFile{path, n}
+		}
+
+		value := strings.TrimSpace(parts[1])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+
+		if section != "" {
+			key = section + "." + key
+		}
+		config.Values[key] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+/*---------------------*/
+
+// SetEnvVar sets the name of the environment variable providing the value of the flag
+// when the flag is missing in the command-line arguments.
+func (f *FlagCommand) SetEnvVar(name string) *FlagCommand {
+	f.EnvVar = removeWhitespaces(name)
+	return f
+}
+
+// return the value of the environment variable
+func (registry *Registry) lookupEnv(name string) (string, bool) {
+	if registry.LookupEnv != nil {
+		return registry.LookupEnv(name)
+	}
+	return os.LookupEnv(name)
+}
+
+// check and normalize a value of the flag from the environment or configuration
+func (f *FlagCommand) normalize(value string) (string, error) {
+	if f.IsBoolean {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", ErrorUnsupportedValue{f.Name, f.redact(value)}
+		}
+		return strconv.FormatBool(b), nil
+	}
+
+	if !f.Validate(value) {
+		return "", ErrorUnsupportedValue{f.Name, f.redact(value)}
+	}
+
+	return value, nil
+}
+
+// get the values of the flags missing in the command-line arguments from the environment and configurations
+func (registry *Registry) lookupValues(commandConfig *CommandConfig, store *CommandParsed) error {
+	for _, name := range commandConfig.FlagNames() {
+		flag := commandConfig.Flags[name]
+		if _, ok := store.Flags[name]; ok || flag.ReplacedBy != "" {
+			continue
+		}
+
+		if flag.EnvVar != "" {
+			if value, ok := registry.lookupEnv(flag.EnvVar); ok {
+				value, err := flag.normalize(value)
+				if err != nil {
+					return err
+				}
+				store.Flags[name] = flag.StoreFrom(value, SourceEnv, flag.EnvVar)
+				continue
+			}
+		}
+
+		for _, config := range registry.Configs {
+			if value, key, ok := config.LookupFlag(commandConfig.Name, name); ok {
+				value, err := flag.normalize(value)
+				if err != nil {
+					return err
+				}
+				store.Flags[name] = flag.StoreFrom(value, SourceConfig, config.String()+":"+key)
+				break
+			}
+		}
+	}
+
+	return nil
+}
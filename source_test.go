@@ -0,0 +1,145 @@
+package clapper_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/msaf1980/clapper"
+	"github.com/msaf1980/clapper/clappertest"
+)
+
+// test sources of the values
+func TestValueSources(t *testing.T) {
+	tests := map[string]struct {
+		fixture clappertest.Fixture
+		want    map[string]clapper.Flag
+	}{
+		"default": {
+			fixture: clappertest.Fixture{Args: []string{"info"}},
+			want: map[string]clapper.Flag{
+				"verbose": {Value: "false", Source: clapper.SourceDefault},
+				"output":  {Value: "./", Source: clapper.SourceDefault},
+			},
+		},
+		"argv": {
+			fixture: clappertest.Fixture{
+				Args: []string{"info", "-v", "-o", "/tmp"},
+				Env:  map[string]string{"APP_OUTPUT": "/env"},
+			},
+			want: map[string]clapper.Flag{
+				"verbose": {Value: "true", Source: clapper.SourceArgv},
+				"output":  {Value: "/tmp", Source: clapper.SourceArgv},
+			},
+		},
+		"env": {
+			fixture: clappertest.Fixture{
+				Args:   []string{"info"},
+				Env:    map[string]string{"APP_VERBOSE": "1", "APP_OUTPUT": "/env"},
+				Config: map[string]string{"output": "/config"},
+			},
+			want: map[string]clapper.Flag{
+				"verbose": {Value: "true", Source: clapper.SourceEnv, SourceName: "APP_VERBOSE"},
+				"output":  {Value: "/env", Source: clapper.SourceEnv, SourceName: "APP_OUTPUT"},
+			},
+		},
+		"config": {
+			fixture: clappertest.Fixture{
+				Args:   []string{"info"},
+				Config: map[string]string{"output": "/config", "info.version": "2.0.0"},
+			},
+			want: map[string]clapper.Flag{
+				"output":  {Value: "/config", Source: clapper.SourceConfig, SourceName: "fixture:output"},
+				"version": {Value: "2.0.0", Source: clapper.SourceConfig, SourceName: "fixture:info.version"},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			registry := newDemoRegistry(false)
+			registry.Commands["info"].Flags["verbose"].SetEnvVar("APP_VERBOSE")
+			registry.Commands["info"].Flags["output"].SetEnvVar("APP_OUTPUT")
+
+			result := clappertest.Run(t, registry, tt.fixture)
+			if result.Err != nil {
+				t.Fatal(result.Err)
+			}
+
+			for flagName, want := range tt.want {
+				flag := result.Command.Flags[flagName]
+				if flag.Value != want.Value || flag.Source != want.Source || flag.SourceName != want.SourceName {
+					t.Errorf("flag(%s) is %#v, want %#v", flagName, flag, want)
+				}
+				if flag.IsSet() != (want.Source != clapper.SourceDefault) || flag.Changed() != (want.Source == clapper.SourceArgv) {
+					t.Errorf("flag(%s) is set %t, changed %t", flagName, flag.IsSet(), flag.Changed())
+				}
+			}
+		})
+	}
+}
+
+// test invalid values from the environment and configuration
+func TestInvalidSourceValues(t *testing.T) {
+	registry := newDemoRegistry(false)
+	registry.Commands["info"].Flags["verbose"].SetEnvVar("APP_VERBOSE")
+
+	result := clappertest.Run(t, registry, clappertest.Fixture{
+		Args: []string{"info"},
+		Env:  map[string]string{"APP_VERBOSE": "maybe"},
+	})
+	clappertest.AssertError(t, result.Err, clapper.ErrorUnsupportedValue{Name: "verbose", Value: "maybe"})
+
+	result = clappertest.Run(t, registry, clappertest.Fixture{
+		Args:   []string{"info"},
+		Config: map[string]string{"version": "3.0.0"},
+	})
+	clappertest.AssertError(t, result.Err, clapper.ErrorUnsupportedValue{Name: "version", Value: "3.0.0"})
+}
+
+// test sources of the arguments
+func TestArgSources(t *testing.T) {
+	command := clappertest.Parse(t, newDemoRegistry(false), "info")
+	if arg := command.Args["category"]; arg.Source != clapper.SourceDefault || arg.IsSet() || arg.Changed() {
+		t.Errorf("got %#v", arg)
+	}
+
+	command = clappertest.Parse(t, newDemoRegistry(false), "info", "student")
+	if arg := command.Args["category"]; arg.Source != clapper.SourceArgv || !arg.IsSet() || !arg.Changed() {
+		t.Errorf("got %#v", arg)
+	}
+}
+
+// test configuration files
+func TestReadConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clapper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.conf")
+	if err := ioutil.WriteFile(path, []byte("# comment\noutput = \"/my dir\"\n\n[info]\nversion=2.0.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := clapper.ReadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry := newDemoRegistry(false)
+	registry.Configs = []clapper.ConfigSource{config}
+	command := clappertest.Parse(t, registry, "info")
+	clappertest.AssertFlags(t, command, map[string]string{"output": "/my dir", "version": "2.0.0"})
+	if got, want := command.Flags["version"].SourceName, path+":info.version"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	bad := filepath.Join(dir, "bad.conf")
+	if err := ioutil.WriteFile(bad, []byte("\n; comment\noutput\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = clapper.ReadConfigFile(bad)
+	clappertest.AssertError(t, err, clapper.ErrorConfigFile{File: bad, Line: 3})
+}
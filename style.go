@@ -0,0 +1,171 @@
+package clapper
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ColorMode type represents the use of the colors in the help and the messages (see `Registry.Color`).
+type ColorMode int
+
+// color modes
+const (
+	// ColorAuto mode uses the colors only if the output is a terminal and `NO_COLOR` environment variable is not set.
+	ColorAuto ColorMode = iota
+
+	// ColorAlways mode always uses the colors.
+	ColorAlways
+
+	// ColorNever mode never uses the colors.
+	ColorNever
+)
+
+// Theme represents the styles of the colored output (see `Registry.Theme`).
+// A style is a list of ANSI SGR parameters separated by `;`, for example `1;36` for bold cyan, "" for the plain text.
+type Theme struct {
+	// style of the program and command names
+	Command string
+
+	// style of the argument names
+	Arg string
+
+	// style of the flag names
+	Flag string
+
+	// style of the section headings of the help
+	Heading string
+
+	// style of the error messages
+	Error string
+
+	// style of the warnings
+	Warning string
+}
+
+// DefaultTheme is the theme used if `Registry.Theme` is nil.
+var DefaultTheme = Theme{
+	Command: "1",
+	Arg:     "36",
+	Flag:    "32",
+	Heading: "1;4",
+	Error:   "31",
+	Warning: "33",
+}
+
+/*---------------------*/
+
+// minimal width of the wrapped descriptions of the help
+const minWrapWidth = 20
+
+// style and width of the output written to a writer
+type output struct {
+	// if true, the texts are painted with the styles of the theme
+	color bool
+
+	// styles of the texts
+	theme Theme
+
+	// width of the lines, 0 if the lines are not wrapped
+	width int
+}
+
+// return the output settings of the writer (nil for the text returned by `Registry.Help`)
+func (registry *Registry) output(w io.Writer) output {
+	o := output{theme: DefaultTheme}
+	if registry.Theme != nil {
+		o.theme = *registry.Theme
+	}
+
+	f, isFile := w.(*os.File)
+	terminal := isFile && isTerminal(f)
+
+	switch registry.Color {
+	case ColorAlways:
+		o.color = true
+	case ColorAuto:
+		term, _ := registry.lookupEnv("TERM")
+		noColor, _ := registry.lookupEnv("NO_COLOR")
+		o.color = terminal && noColor == "" && term != "dumb"
+	}
+
+	o.width = registry.Width
+	if o.width <= 0 && terminal {
+		o.width = terminalWidth(f)
+	}
+	if o.width <= 0 {
+		if columns, ok := registry.lookupEnv("COLUMNS"); ok {
+			o.width, _ = strconv.Atoi(strings.TrimSpace(columns))
+		}
+	}
+	if o.width < 0 {
+		o.width = 0
+	}
+
+	return o
+}
+
+// paint the text with the style if the colors are enabled
+func (o output) paint(style string, text string) string {
+	if !o.color || style == "" || text == "" {
+		return text
+	}
+	return "\x1b[" + style + "m" + text + "\x1b[0m"
+}
+
+// wrap the text to the width of the output, the lines after the first one are indented by `indent` columns
+func (o output) wrap(text string, indent int) string {
+	width := o.width - indent
+	if o.width == 0 || width < minWrapWidth || utf8.RuneCountInString(text) <= width {
+		return text
+	}
+
+	var b strings.Builder
+	column := 0
+	for _, word := range strings.Fields(text) {
+		length := utf8.RuneCountInString(word)
+		if column > 0 && column+1+length > width {
+			b.WriteString("\n" + strings.Repeat(" ", indent))
+			column = 0
+		} else if column > 0 {
+			b.WriteString(" ")
+			column++
+		}
+		b.WriteString(word)
+		column += length
+	}
+
+	return b.String()
+}
+
+// row of a table of the help: an indented name and its description
+type row struct {
+	name        string
+	style       string
+	description string
+}
+
+// write the rows aligning the descriptions in a column (three spaces after the longest name),
+// the descriptions are wrapped to the width of the output
+func (o output) writeTable(w *bytes.Buffer, rows []row) {
+	column := 0
+	for _, r := range rows {
+		if length := utf8.RuneCountInString(r.name); length > column {
+			column = length
+		}
+	}
+	column += 3
+
+	for _, r := range rows {
+		name := strings.TrimLeft(r.name, " ")
+		w.WriteString(r.name[:len(r.name)-len(name)] + o.paint(r.style, name))
+		if r.description != "" {
+			w.WriteString(strings.Repeat(" ", column-utf8.RuneCountInString(r.name)))
+			w.WriteString(o.wrap(r.description, column))
+		}
+		w.WriteString("\n")
+	}
+}
@@ -0,0 +1,92 @@
+package clapper_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/msaf1980/clapper"
+	"github.com/msaf1980/clapper/clappertest"
+)
+
+// test the colored help and error messages
+func TestColor(t *testing.T) {
+	registry := newDemoRegistry(false)
+
+	// the output isn't a terminal
+	var stdout bytes.Buffer
+	if err := registry.WriteHelp(&stdout, "info"); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(stdout.String(), "\x1b[") {
+		t.Errorf("got colored help %q", stdout.String())
+	}
+
+	registry.Color = clapper.ColorAlways
+	help := clappertest.Help(t, registry, "info")
+	for _, want := range []string{
+		"Usage: \x1b[1mdemo\x1b[0m \x1b[1minfo\x1b[0m [flags]",
+		"\x1b[1;4mFlags:\x1b[0m\n",
+		"  \x1b[32m-o, --output <value>\x1b[0m    (default: ./)\n",
+		"  \x1b[36mcategory\x1b[0m   (default: manager)",
+	} {
+		if !strings.Contains(help, want) {
+			t.Errorf("help %q doesn't contain %q", help, want)
+		}
+	}
+
+	var stderr bytes.Buffer
+	registry.Stderr = &stderr
+	registry.Theme = &clapper.Theme{Error: "1;31"}
+	registry.HandleError(errors.New("failed"))
+	if want := "demo: \x1b[1;31mfailed\x1b[0m\n"; stderr.String() != want {
+		t.Errorf("got %q, want %q", stderr.String(), want)
+	}
+
+	registry.Color = clapper.ColorNever
+	if help := clappertest.Help(t, registry, "info"); strings.Contains(help, "\x1b[") {
+		t.Errorf("got colored help %q", help)
+	}
+}
+
+// test the help wrapped to the width
+func TestWidth(t *testing.T) {
+	registry := clapper.NewRegistry()
+	registry.Name = "demo"
+	rootCommand, _ := registry.Register("")
+	rootCommand.SetDescription("The demo command shows how the long descriptions are wrapped to the width of the terminal.")
+	flag, _ := rootCommand.AddFlag("output", "o", false, "")
+	flag.SetDescription("directory of the generated files, it is created if it doesn't exist")
+
+	registry.Width = 50
+	help := clappertest.Help(t, registry, "")
+	for _, line := range strings.Split(help, "\n") {
+		if utf8.RuneCountInString(line) > 50 {
+			t.Errorf("line %q is longer than 50 columns", line)
+		}
+	}
+	want := "  -o, --output <value>   directory of the\n" +
+		"                         generated files, it is\n"
+	if !strings.Contains(help, want) {
+		t.Errorf("help %q doesn't contain %q", help, want)
+	}
+
+	// the width of `COLUMNS` environment variable
+	registry.Width = 0
+	registry.LookupEnv = func(name string) (string, bool) {
+		if name == "COLUMNS" {
+			return "60", true
+		}
+		return "", false
+	}
+	if help := clappertest.Help(t, registry, ""); !strings.Contains(help, "\nThe demo command shows how the long descriptions are wrapped\nto the width") {
+		t.Errorf("got help %q", help)
+	}
+
+	registry.LookupEnv = func(name string) (string, bool) { return "", false }
+	if help := clappertest.Help(t, registry, ""); !strings.Contains(help, registry.Commands[""].Description) {
+		t.Errorf("got help %q", help)
+	}
+}
@@ -0,0 +1,139 @@
+package clapper
+
+import "strings"
+
+// FlagSyntax interface converts the flags of an alternative syntax to the getopt(3) syntax (see `Registry.Syntaxes`).
+type FlagSyntax interface {
+
+	// Flag returns the getopt(3) form (`-s`, `--name` or `--name=value`) of the command-line value
+	// and `true` if the value is a flag of the command in this syntax.
+	Flag(commandConfig *CommandConfig, value string) (string, bool)
+}
+
+// SlashSyntax accepts DOS/Windows style flags: `/name`, `/s` and `/name:value` (or `/name=value`).
+// A value is a flag only if the name is registered for the command, so the absolute paths are still arguments.
+type SlashSyntax struct{}
+
+// Flag method implements `FlagSyntax` interface.
+func (SlashSyntax) Flag(commandConfig *CommandConfig, value string) (string, bool) {
+	if len(value) < 2 || value[0] != '/' {
+		return "", false
+	}
+
+	name, flagValue, hasValue := value[1:], "", false
+	if i := strings.IndexAny(name, ":="); i != -1 {
+		name, flagValue, hasValue = name[:i], name[i+1:], true
+	}
+
+	flag, ok := commandConfig.canonicalName(name)
+	if !ok {
+		return "", false
+	}
+	if hasValue {
+		return flag + "=" + flagValue, true
+	}
+	return flag, true
+}
+
+// PlusSyntax accepts `+name` and `+s` positive toggles setting boolean flags to `true`.
+// A value is a flag only if it is a registered boolean flag of the command, so `+1` is still an argument.
+type PlusSyntax struct{}
+
+// Flag method implements `FlagSyntax` interface.
+func (PlusSyntax) Flag(commandConfig *CommandConfig, value string) (string, bool) {
+	if len(value) < 2 || value[0] != '+' {
+		return "", false
+	}
+
+	flag, ok := commandConfig.canonicalName(value[1:])
+	if !ok || strings.HasPrefix(flag, "--no-") {
+		return "", false
+	}
+	if f := commandConfig.lookupFlag(flag); !f.IsBoolean || f.IsInverted {
+		return "", false
+	}
+	return flag, true
+}
+
+// SingleDashSyntax accepts long flags with a single dash: `-name`, `-name value` and `-name=value`
+// (like `find` or the standard `flag` package). A registered short name takes precedence over a long name.
+type SingleDashSyntax struct{}
+
+// Flag method implements `FlagSyntax` interface.
+func (SingleDashSyntax) Flag(commandConfig *CommandConfig, value string) (string, bool) {
+	if len(value) < 2 || value[0] != '-' || value[1] == '-' {
+		return "", false
+	}
+
+	name, rest := value[1:], ""
+	if i := strings.Index(name, "="); i != -1 {
+		name, rest = name[:i], name[i:]
+	}
+
+	flag, ok := commandConfig.canonicalName(name)
+	if !ok {
+		return "", false
+	}
+	return flag + rest, true
+}
+
+/*---------------------*/
+
+// return the getopt(3) form of a flag name (a short name takes precedence)
+func (commandConfig *CommandConfig) canonicalName(name string) (string, bool) {
+	if _, ok := commandConfig.flagsShort[name]; ok && len(name) == 1 {
+		return "-" + name, true
+	}
+	if commandConfig.lookupFlag("--"+name) != nil {
+		return "--" + name, true
+	}
+	return "", false
+}
+
+// return the getopt(3) form of a command-line value using the alternative syntaxes of the registry
+func (registry *Registry) canonicalFlag(commandConfig *CommandConfig, value string) string {
+	if commandConfig == nil {
+		return value
+	}
+
+	for _, syntax := range registry.Syntaxes {
+		if flag, ok := syntax.Flag(commandConfig, value); ok {
+			return flag
+		}
+	}
+
+	return value
+}
+
+// convert the values before `--` to the getopt(3) syntax,
+// the value of a non-boolean flag (the next value or the value after `=`) is not converted
+func (registry *Registry) canonicalFlags(commandConfig *CommandConfig, values []string) []string {
+	if len(registry.Syntaxes) == 0 {
+		return values
+	}
+
+	canonical := make([]string, len(values))
+	for i := 0; i < len(values); i++ {
+		if isEndOfFlags(values[i]) {
+			copy(canonical[i:], values[i:])
+			break
+		}
+		canonical[i] = registry.canonicalFlag(commandConfig, values[i])
+
+		if commandConfig.takesValue(canonical[i]) && i+1 < len(values) && !isFlag(values[i+1]) {
+			i++
+			canonical[i] = values[i]
+		}
+	}
+
+	return canonical
+}
+
+// check if the getopt(3) form of a value is a non-boolean flag of the command taking the next value
+func (commandConfig *CommandConfig) takesValue(value string) bool {
+	if !isFlag(value) || isEndOfFlags(value) || strings.Contains(value, "=") {
+		return false
+	}
+	flag := commandConfig.lookupFlagValue(value)
+	return flag != nil && !flag.IsBoolean
+}
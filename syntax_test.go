@@ -0,0 +1,116 @@
+package clapper_test
+
+import (
+	"testing"
+
+	"github.com/msaf1980/clapper"
+	"github.com/msaf1980/clapper/clappertest"
+)
+
+// test the alternative flag syntaxes
+func TestFlagSyntaxes(t *testing.T) {
+	tests := []struct {
+		name     string
+		syntaxes []clapper.FlagSyntax
+		args     []string
+		command  string
+		flags    map[string]string
+		argVals  map[string]string
+	}{
+		{
+			name:     "slash",
+			syntaxes: []clapper.FlagSyntax{clapper.SlashSyntax{}},
+			args:     []string{"/dir:C:\\out", "/f", "/usr/bin"},
+			flags:    map[string]string{"dir": "C:\\out", "force": "true"},
+			argVals:  map[string]string{"output": "/usr/bin"},
+		},
+		{
+			name:     "slash command",
+			syntaxes: []clapper.FlagSyntax{clapper.SlashSyntax{}},
+			args:     []string{"info", "/verbose", "/o=out", "/no-clean", "student"},
+			command:  "info",
+			flags:    map[string]string{"verbose": "true", "output": "out", "clean": "false"},
+			argVals:  map[string]string{"category": "student"},
+		},
+		{
+			name:     "slash value",
+			syntaxes: []clapper.FlagSyntax{clapper.SlashSyntax{}, clapper.PlusSyntax{}},
+			args:     []string{"info", "--output", "/v", "student", "-o", "/o"},
+			command:  "info",
+			flags:    map[string]string{"verbose": "false", "output": "/o"},
+			argVals:  map[string]string{"category": "student"},
+		},
+		{
+			name:     "slash value assignment",
+			syntaxes: []clapper.FlagSyntax{clapper.SlashSyntax{}, clapper.PlusSyntax{}},
+			args:     []string{"info", "/o:/v", "student"},
+			command:  "info",
+			flags:    map[string]string{"verbose": "false", "output": "/v"},
+			argVals:  map[string]string{"category": "student"},
+		},
+		{
+			name:     "plus value",
+			syntaxes: []clapper.FlagSyntax{clapper.PlusSyntax{}},
+			args:     []string{"info", "--output", "+verbose", "student"},
+			command:  "info",
+			flags:    map[string]string{"verbose": "false", "output": "+verbose"},
+			argVals:  map[string]string{"category": "student"},
+		},
+		{
+			name:     "plus",
+			syntaxes: []clapper.FlagSyntax{clapper.PlusSyntax{}},
+			args:     []string{"+force", "+1"},
+			flags:    map[string]string{"force": "true"},
+			argVals:  map[string]string{"output": "+1"},
+		},
+		{
+			name:     "single dash",
+			syntaxes: []clapper.FlagSyntax{clapper.SingleDashSyntax{}},
+			args:     []string{"-dir", "out", "-version=1.0", "-v", "-f", "--", "-path"},
+			flags:    map[string]string{"dir": "out", "version": "1.0", "verbose": "true", "force": "true"},
+			argVals:  map[string]string{"output": "-path"},
+		},
+		{
+			name:     "getopt",
+			syntaxes: []clapper.FlagSyntax{clapper.SlashSyntax{}, clapper.PlusSyntax{}, clapper.SingleDashSyntax{}},
+			args:     []string{"info", "-v", "--output=out", "+verbose", "-output", "dir"},
+			command:  "info",
+			flags:    map[string]string{"verbose": "true", "output": "dir"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := newDemoRegistry(true)
+			registry.Syntaxes = tt.syntaxes
+
+			command := clappertest.Parse(t, registry, tt.args...)
+			clappertest.AssertCommand(t, command, tt.command)
+			clappertest.AssertFlags(t, command, tt.flags)
+			clappertest.AssertArgs(t, command, tt.argVals)
+		})
+	}
+}
+
+// test values which are not flags of the alternative syntaxes
+func TestFlagSyntaxErrors(t *testing.T) {
+	registry := newDemoRegistry(true)
+	result := clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"-dir", "out"}})
+	clappertest.AssertError(t, result.Err, clapper.ErrorUnsupportedFlag{Name: "-dir"})
+
+	registry.Syntaxes = []clapper.FlagSyntax{clapper.SingleDashSyntax{}}
+	result = clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"-unknown"}})
+	clappertest.AssertError(t, result.Err, clapper.ErrorUnsupportedFlag{Name: "-unknown"})
+
+	// only positive toggles of the boolean flags
+	registry.Syntaxes = []clapper.FlagSyntax{clapper.PlusSyntax{}}
+	command := clappertest.Parse(t, registry, "+dir")
+	clappertest.AssertArgs(t, command, map[string]string{"output": "+dir"})
+
+	// raw values of the occurrences
+	registry.Syntaxes = []clapper.FlagSyntax{clapper.SlashSyntax{}}
+	command = clappertest.Parse(t, registry, "/dir:out")
+	if occurrence := command.Occurrences[0]; occurrence.Raw != "/dir:out" || occurrence.Value != "out" {
+		t.Errorf("got %#v", occurrence)
+	}
+}
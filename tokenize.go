@@ -0,0 +1,145 @@
+package clapper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrorUnterminatedQuote represents an error when a quoted string is not closed.
+// The `Line` and `Column` fields are the position (starting from 1) of the opening quote.
+type ErrorUnterminatedQuote struct {
+	// the opening quote: `'` or `"`
+	Quote  rune
+	Line   int
+	Column int
+}
+
+func (e ErrorUnterminatedQuote) Error() string {
+	return fmt.Sprintf("unterminated %s quote at line %d, column %d", e.kind(), e.Line, e.Column)
+}
+
+// return the kind of the quote used in the messages: `single` or `double`
+func (e ErrorUnterminatedQuote) kind() string {
+	if e.Quote == '"' {
+		return "double"
+	}
+	return "single"
+}
+
+/*---------------------*/
+
+// token of a command line with the position (starting from 1) of its first character
+type token struct {
+	value  string
+	line   int
+	column int
+}
+
+// split text to tokens using POSIX shell quoting rules:
+// single quotes preserve all characters, double quotes preserve all characters except `\` escapes
+// of `"`, `\`, `$`, "`" and a newline, a `\` outside quotes escapes the next character (`\` with a newline is removed),
+// a `#` at the beginning of a token starts a comment until the end of the line.
+// No expansion of variables, globs, etc. is performed.
+func tokenize(text string) ([]token, error) {
+	tokens := make([]token, 0)
+	runes := []rune(text)
+
+	// line and column of each character
+	lines := make([]int, len(runes))
+	columns := make([]int, len(runes))
+	line, column := 1, 1
+	for i, r := range runes {
+		lines[i], columns[i] = line, column
+		if r == '\n' {
+			line, column = line+1, 1
+		} else {
+			column++
+		}
+	}
+
+	var (
+		buf     strings.Builder
+		current *token // token being read (nil between tokens)
+	)
+
+	// start a new token at the position of the i-th character if none is started
+	start := func(i int) {
+		if current == nil {
+			current = &token{line: lines[i], column: columns[i]}
+		}
+	}
+
+	// finish the current token
+	finish := func() {
+		if current != nil {
+			current.value = buf.String()
+			tokens = append(tokens, *current)
+			current = nil
+			buf.Reset()
+		}
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			finish()
+
+		case r == '#' && current == nil:
+			// skip comment till the end of the line
+			for i+1 < len(runes) && runes[i+1] != '\n' {
+				i++
+			}
+
+		case r == '\\':
+			if i+1 < len(runes) && runes[i+1] == '\n' {
+				i++ // line continuation
+				continue
+			}
+			start(i)
+			if i+1 < len(runes) {
+				i++
+				buf.WriteRune(runes[i])
+			} else {
+				buf.WriteRune(r) // trailing backslash is kept as is
+			}
+
+		case r == '\'' || r == '"':
+			start(i)
+			quote := i
+
+			closed := false
+			for i+1 < len(runes) {
+				i++
+				c := runes[i]
+
+				if c == r {
+					closed = true
+					break
+				}
+
+				if r == '"' && c == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`\n", runes[i+1]) {
+					i++
+					if runes[i] == '\n' {
+						continue // line continuation
+					}
+					c = runes[i]
+				}
+
+				buf.WriteRune(c)
+			}
+
+			if !closed {
+				return nil, ErrorUnterminatedQuote{r, lines[quote], columns[quote]}
+			}
+
+		default:
+			start(i)
+			buf.WriteRune(r)
+		}
+	}
+	finish()
+
+	return tokens, nil
+}
@@ -0,0 +1,16 @@
+package clapper
+
+// SetAllowUnknownFlags sets if the unregistered flags of the command are collected in `CommandParsed.Unknown`
+// instead of returning `ErrorUnknownFlag` error, for example to forward them to a child process.
+// An unknown flag takes the next value as its value unless the value is a flag or `--`
+// (use `--<flag>=<value>` form for the values starting with `-`). The flags which are not supported by the parser,
+// for example `-abc`, `-Xmx512m` or `---x`, are collected as unknown flags instead of `ErrorUnsupportedFlag` error.
+func (commandConfig *CommandConfig) SetAllowUnknownFlags(allow bool) *CommandConfig {
+	commandConfig.AllowUnknownFlags = allow
+	return commandConfig
+}
+
+// check if the unknown flags of the command are collected
+func (registry *Registry) allowUnknownFlags(commandConfig *CommandConfig) bool {
+	return registry.AllowUnknownFlags || (commandConfig != nil && commandConfig.AllowUnknownFlags)
+}
@@ -0,0 +1,89 @@
+package clapper_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/msaf1980/clapper"
+	"github.com/msaf1980/clapper/clappertest"
+)
+
+// test the collection of the unknown flags
+func TestUnknownFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		unknown []string
+		flags   map[string]string
+		argVals map[string]string
+	}{
+		{
+			name:    "none",
+			args:    []string{"info", "-v", "math"},
+			unknown: []string{},
+			flags:   map[string]string{"verbose": "true", "output": "./"},
+			argVals: map[string]string{"category": "math", "username": ""},
+		},
+		{
+			name:    "values",
+			args:    []string{"info", "--color", "auto", "-x", "-v", "--depth=2", "math", "-o", "out", "-i", "a"},
+			unknown: []string{"--color", "auto", "-x", "--depth=2", "-i", "a"},
+			flags:   map[string]string{"verbose": "true", "output": "out"},
+			argVals: map[string]string{"category": "math", "username": ""},
+		},
+		{
+			name:    "inverted",
+			args:    []string{"info", "--no-color", "--", "math", "-a"},
+			unknown: []string{"--no-color"},
+			flags:   map[string]string{"verbose": "false", "output": "./"},
+			argVals: map[string]string{"category": "math", "username": "-a"},
+		},
+		{
+			name:    "unsupported",
+			args:    []string{"info", "-abc", "-Xmx512m", "---x=1", "-v", "math", "-o", "out"},
+			unknown: []string{"-abc", "-Xmx512m", "---x=1"},
+			flags:   map[string]string{"verbose": "true", "output": "out"},
+			argVals: map[string]string{"category": "math", "username": ""},
+		},
+		{
+			name:    "empty value",
+			args:    []string{"info", "--color=", "math", "x"},
+			unknown: []string{"--color="},
+			flags:   map[string]string{"verbose": "false", "output": "./"},
+			argVals: map[string]string{"category": "math", "username": "x"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := newDemoRegistry(true)
+			registry.Commands["info"].SetAllowUnknownFlags(true)
+
+			command := clappertest.Parse(t, registry, tt.args...)
+			if !reflect.DeepEqual(command.Unknown, tt.unknown) {
+				t.Errorf("got unknown %q, want %q", command.Unknown, tt.unknown)
+			}
+			clappertest.AssertFlags(t, command, tt.flags)
+			clappertest.AssertArgs(t, command, tt.argVals)
+		})
+	}
+
+	// unknown flags are an error unless allowed
+	registry := newDemoRegistry(true)
+	result := clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"--color"}})
+	clappertest.AssertError(t, result.Err, clapper.ErrorUnknownFlag{Name: "--color"})
+	result = clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"-abc"}})
+	clappertest.AssertError(t, result.Err, clapper.ErrorUnsupportedFlag{Name: "-abc"})
+
+	registry.AllowUnknownFlags = true
+	command := clappertest.Parse(t, registry, "--color", "-v", "---x")
+	if want := []string{"--color", "---x"}; !reflect.DeepEqual(command.Unknown, want) {
+		t.Errorf("got unknown %q, want %q", command.Unknown, want)
+	}
+
+	// the unknown flags are reproduced
+	command = clappertest.Parse(t, registry, "info", "math", "--color", "auto", "-v")
+	if got, want := clapper.Join(command.Values()), "info --color auto math --verbose"; got != want {
+		t.Errorf("got values %q, want %q", got, want)
+	}
+}
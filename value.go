@@ -0,0 +1,167 @@
+package clapper
+
+import "flag"
+
+// Value interface is implemented by the custom types of the flag values, for example log levels or versions.
+// It is a `flag.Value` with the name of the type shown in the help and errors. A value with
+// an `IsBoolFlag() bool` method returning `true` is a boolean value (see `flag.FlagSet.Var`).
+type Value interface {
+	flag.Value
+
+	// Type returns the name of the type, for example `level`.
+	Type() string
+}
+
+// Validator interface is implemented by the bound values which can check a value without setting it.
+// The values of the bound flags and arguments are set after a successful parse, the values of all bound values
+// implementing `Validator` are validated before any of them is set, so a rejected value doesn't leave
+// the variables of the other flags and arguments changed.
+type Validator interface {
+	// Validate returns an error if `Set` would reject the value.
+	Validate(value string) error
+}
+
+// AddFlagValue registers a command-line flag backed by a custom type value.
+// The default value of the flag is the current value (`Value.String`) and the value is set with `Value.Set`
+// after a successful parse, so it is parsed and validated by the type (see `FlagCommand.SetFlagValue`).
+// The `name` and `shortName` arguments are the same as in `AddFlag`.
+// If the flag is already registered, the registered `*FlagCommand` object is returned and second return value will be `true`.
+func (commandConfig *CommandConfig) AddFlagValue(name string, shortName string, value Value) (*FlagCommand, bool) {
+	flag, exist := commandConfig.AddFlag(name, shortName, isBoolValue(value), value.String())
+	if exist {
+		return flag, true
+	}
+
+	flag.SetFlagValue(value)
+	if flag.IsBoolean && !flag.IsInverted {
+		flag.DefaultValue = value.String()
+	}
+
+	return flag, false
+}
+
+// Type returns the type name of the flag value: `Value.Type` of a custom type value,
+// "bool" for a boolean flag and "" for other flags.
+func (f *FlagCommand) Type() string {
+	if value, ok := f.FlagValue.(Value); ok {
+		return value.Type()
+	}
+	if f.IsBoolean {
+		return "bool"
+	}
+	return ""
+}
+
+// AddArgValue registers an argument backed by a custom type value. The default value of the argument is
+// the current value (`Value.String`) and each value of the argument (each value of a variadic argument)
+// is set with `Value.Set` after a successful parse, so it is parsed and validated by the type (see `ArgCommand.SetArgValue`).
+// The `name` argument is the same as in `AddArg`.
+// If the argument is already registered, the registered `*ArgCommand` object is returned and second return value will be `true`.
+func (commandConfig *CommandConfig) AddArgValue(name string, value Value) (*ArgCommand, bool) {
+	arg, exist := commandConfig.AddArg(name, value.String())
+	if exist {
+		return arg, true
+	}
+
+	arg.SetArgValue(value)
+	return arg, false
+}
+
+// SetArgValue binds the argument to a `flag.Value`. After a successful parse, each value of the argument
+// found in the command-line arguments (or asked by the prompter) is set with `flag.Value.Set`
+// in the order of the command-line arguments, for example to append the values of a variadic argument to a list.
+// The parsed value of a non-variadic `Value` is replaced with its `Value.String` result.
+// The values are validated before any bound value is set if the bound value implements `Validator`.
+// Parsing of a registry with bound arguments is not safe for concurrent use.
+func (a *ArgCommand) SetArgValue(value flag.Value) *ArgCommand {
+	a.ArgValue = value
+	return a
+}
+
+// Type returns the type name of the argument value: `Value.Type` of a custom type value and "" for other arguments.
+func (a *ArgCommand) Type() string {
+	if value, ok := a.ArgValue.(Value); ok {
+		return value.Type()
+	}
+	return ""
+}
+
+// return the parsed values of the bound arguments
+func (commandConfig *CommandConfig) argBindings(store *CommandParsed) []binding {
+	bindings := make([]binding, 0)
+	for _, name := range commandConfig.ArgNames {
+		a := commandConfig.Args[name]
+		parsed, ok := store.Args[name]
+		if a.ArgValue == nil || !ok || !parsed.IsSet() {
+			continue
+		}
+
+		// the values found in the command-line arguments
+		values := make([]string, 0)
+		for _, occurrence := range store.Occurrences {
+			if !occurrence.IsFlag && occurrence.Name == name {
+				values = append(values, occurrence.Value)
+			}
+		}
+		if len(values) == 0 {
+			values = append(values, parsed.Value)
+		}
+
+		for _, value := range values {
+			value := value
+			bindings = append(bindings, binding{
+				target: a.ArgValue,
+				value:  value,
+				fail: func(err error) error {
+					return ErrorArgValue{a.Name, a.Type(), value, err}
+				},
+			})
+		}
+
+		// the value of a custom type is normalized by the type
+		if typed, ok := a.ArgValue.(Value); ok && !a.IsVariadic {
+			bindings[len(bindings)-1].done = func() {
+				parsed.Value = typed.String()
+			}
+		}
+	}
+
+	return bindings
+}
+
+// value of a bound flag or argument set after a successful parse
+type binding struct {
+	// bound value
+	target flag.Value
+
+	// value to set
+	value string
+
+	// return the error of the flag or argument rejecting the value
+	fail func(err error) error
+
+	// called after the value is set (nil if nothing to do)
+	done func()
+}
+
+// validate the values of the bindings (see `Validator`) and set them
+func setBindings(bindings []binding) error {
+	for _, b := range bindings {
+		if validator, ok := b.target.(Validator); ok {
+			if err := validator.Validate(b.value); err != nil {
+				return b.fail(err)
+			}
+		}
+	}
+
+	for _, b := range bindings {
+		if err := b.target.Set(b.value); err != nil {
+			return b.fail(err)
+		}
+		if b.done != nil {
+			b.done()
+		}
+	}
+
+	return nil
+}
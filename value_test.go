@@ -0,0 +1,148 @@
+package clapper_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/msaf1980/clapper"
+	"github.com/msaf1980/clapper/clappertest"
+)
+
+// log level (custom value type)
+type level int
+
+var levelNames = []string{"debug", "info", "warning", "error"}
+
+func (l *level) Set(s string) error {
+	for i, name := range levelNames {
+		if strings.EqualFold(s, name) {
+			*l = level(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown level %q", s)
+}
+
+func (l *level) String() string {
+	return levelNames[*l]
+}
+
+func (l *level) Type() string {
+	return "level"
+}
+
+func (l *level) Validate(s string) error {
+	var value level
+	return value.Set(s)
+}
+
+// test flags backed by custom value types
+func TestFlagValue(t *testing.T) {
+	logLevel := level(1)
+
+	registry := clapper.NewRegistry()
+	registry.Name = "app"
+	rootCommand, _ := registry.Register("")
+	flag, exist := rootCommand.AddFlagValue("log-level", "l", &logLevel)
+	if exist || flag.DefaultValue != "info" || flag.Type() != "level" {
+		t.Fatalf("got %#v", flag)
+	}
+	flag.SetDescription("minimal level of messages")
+
+	command := clappertest.Parse(t, registry)
+	clappertest.AssertFlags(t, command, map[string]string{"log-level": "info"})
+
+	command = clappertest.Parse(t, registry, "-l", "ERROR")
+	clappertest.AssertFlags(t, command, map[string]string{"log-level": "error"})
+	if logLevel != 3 {
+		t.Errorf("got level %d", logLevel)
+	}
+
+	result := clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"--log-level=trace"}})
+	var valueErr clapper.ErrorFlagValue
+	if !errors.As(result.Err, &valueErr) || valueErr.Type != "level" {
+		t.Fatalf("got %#v", result.Err)
+	}
+	if got, want := result.Err.Error(), `invalid level value log-level=trace found in the arguments: unknown level "trace"`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	help := clappertest.Help(t, registry, "")
+	if !strings.Contains(help, "-l, --log-level <level>   minimal level of messages (default: info)") {
+		t.Errorf("got help\n%s", help)
+	}
+}
+
+// list of log levels (custom value type of a variadic argument)
+type levels []level
+
+func (l *levels) Set(s string) error {
+	var value level
+	if err := value.Set(s); err != nil {
+		return err
+	}
+	*l = append(*l, value)
+	return nil
+}
+
+func (l *levels) String() string {
+	names := make([]string, len(*l))
+	for i := range *l {
+		names[i] = (*l)[i].String()
+	}
+	return strings.Join(names, ",")
+}
+
+func (l *levels) Type() string {
+	return "level"
+}
+
+// test arguments backed by custom value types
+func TestArgValue(t *testing.T) {
+	var minLevel level
+	var filters levels
+
+	registry := clapper.NewRegistry()
+	rootCommand, _ := registry.Register("")
+	arg, exist := rootCommand.AddArgValue("level", &minLevel)
+	if exist || arg.DefaultValue != "debug" || arg.Type() != "level" {
+		t.Fatalf("got %#v", arg)
+	}
+	rootCommand.AddArgValue("filters...", &filters)
+
+	command := clappertest.Parse(t, registry, "WARNING", "info", "Error")
+	clappertest.AssertArgs(t, command, map[string]string{"level": "warning", "filters": "info,Error"})
+	if minLevel != 2 || len(filters) != 2 || filters[0] != 1 || filters[1] != 3 {
+		t.Errorf("got level %d, filters %v", minLevel, filters)
+	}
+
+	result := clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"info", "error", "trace"}})
+	clappertest.AssertError(t, result.Err, clapper.ErrorArgValue{Name: "filters", Type: "level", Value: "trace", Err: errors.New(`unknown level "trace"`)})
+	if got, want := result.Err.Error(), `invalid level argument filters=trace found in the arguments: unknown level "trace"`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// test validation of the bound values before setting them
+func TestValueValidate(t *testing.T) {
+	logLevel := level(1)
+	var minLevel level
+
+	registry := clapper.NewRegistry()
+	rootCommand, _ := registry.Register("")
+	rootCommand.AddFlagValue("log-level", "l", &logLevel)
+	rootCommand.AddArgValue("level", &minLevel)
+
+	result := clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"-l", "error", "trace"}})
+	clappertest.AssertError(t, result.Err, clapper.ErrorArgValue{Name: "level", Type: "level", Value: "trace", Err: errors.New(`unknown level "trace"`)})
+	if logLevel != 1 || minLevel != 0 {
+		t.Errorf("got level %d, minimal level %d", logLevel, minLevel)
+	}
+
+	clappertest.Parse(t, registry, "-l", "error", "warning")
+	if logLevel != 3 || minLevel != 2 {
+		t.Errorf("got level %d, minimal level %d", logLevel, minLevel)
+	}
+}
@@ -0,0 +1,152 @@
+package clapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// VersionCommand is the name of the command registered by `Registry.RegisterVersion`.
+const VersionCommand = "version"
+
+// VersionInfo represents the version of the program printed by the version flags and command
+// (see `CommandConfig.SetVersion` and `Registry.RegisterVersion`).
+type VersionInfo struct {
+	// version of the program (the version of the main module, `(devel)` if it is not versioned)
+	Version string `json:"version"`
+
+	// VCS revision of the build
+	Revision string `json:"revision,omitempty"`
+
+	// time of the VCS revision (RFC 3339)
+	Time string `json:"time,omitempty"`
+
+	// if the working tree had local modifications
+	Modified bool `json:"modified,omitempty"`
+
+	// version of Go used to build the program
+	GoVersion string `json:"goVersion"`
+}
+
+// String method returns the version with the short revision, the modified flag and the Go version,
+// for example `v1.2.0 (revision 1a2b3c4d5e6f, modified, go1.21.0)`.
+func (info VersionInfo) String() string {
+	details := make([]string, 0, 3)
+	if info.Revision != "" {
+		revision := info.Revision
+		if len(revision) > 12 {
+			revision = revision[:12]
+		}
+		details = append(details, "revision "+revision)
+	}
+	if info.Modified {
+		details = append(details, "modified")
+	}
+	if info.GoVersion != "" {
+		details = append(details, info.GoVersion)
+	}
+
+	if len(details) == 0 {
+		return info.Version
+	}
+	return fmt.Sprintf("%s (%s)", info.Version, strings.Join(details, ", "))
+}
+
+// BuildVersion returns the version of the program read from its build information (see `debug.ReadBuildInfo`):
+// the version of the main module, the VCS revision, time and modified flag (stamped by Go 1.18 and later)
+// and the Go version.
+func BuildVersion() VersionInfo {
+	info := VersionInfo{Version: "(devel)", GoVersion: runtime.Version()}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		if build.Main.Version != "" {
+			info.Version = build.Main.Version
+		}
+		readBuildSettings(build, &info)
+	}
+	return info
+}
+
+// return the version info of a version string, the build version if it is empty
+func newVersionInfo(version string) *VersionInfo {
+	info := BuildVersion()
+	if version != "" {
+		info = VersionInfo{Version: version, GoVersion: runtime.Version()}
+	}
+	return &info
+}
+
+// ErrorVersion is returned by `Registry.Parse` when the version of the program is printed to `Registry.Stdout`
+// (see `CommandConfig.SetVersion` and `Registry.RegisterVersion`).
+type ErrorVersion struct{}
+
+func (e ErrorVersion) Error() string {
+	return "version printed"
+}
+
+/*---------------------*/
+
+// SetVersion method enables `--version` and `-V` flags of the command (usually the root command) which make
+// `Registry.Parse` write the version of the program to `Registry.Stdout` and return `ErrorVersion` error,
+// `--version=json` writes it in JSON format. If version is "", the version is read from the build information
+// (see `BuildVersion`). The flags registered by the command take precedence, for example a `-V` flag with a value.
+func (commandConfig *CommandConfig) SetVersion(version string) *CommandConfig {
+	commandConfig.Version = newVersionInfo(version)
+	return commandConfig
+}
+
+// RegisterVersion method registers the `version` command which makes `Registry.Parse` write the version
+// of the program to `Registry.Stdout` and return `ErrorVersion` error, `version --json` writes it in JSON format.
+// If version is "", the version is read from the build information (see `BuildVersion`).
+// If the `version` command is already registered, the registered `*CommandConfig` object is returned unchanged
+// and second return value will be `true`.
+func (registry *Registry) RegisterVersion(version string) (*CommandConfig, bool) {
+	commandConfig, exists := registry.Register(VersionCommand)
+	if exists {
+		return commandConfig, true
+	}
+
+	commandConfig.SetDescription("show the version of the program")
+	commandConfig.AddFlag("json", "", true, "")
+	commandConfig.Version = newVersionInfo(version)
+	commandConfig.printsVersion = true
+
+	return commandConfig, false
+}
+
+// check if the value is the `--version` or `-V` flag of the command (unless the command registers them)
+func isVersionFlag(commandConfig *CommandConfig, value string) bool {
+	if commandConfig.Version == nil || commandConfig.printsVersion {
+		return false
+	}
+
+	switch value {
+	case "--version":
+		_, ok := commandConfig.Flags["version"]
+		return !ok
+	case "-V":
+		_, ok := commandConfig.flagsShort["V"]
+		return !ok
+	}
+
+	return false
+}
+
+// format the names of the version flags of the command
+func versionFlagUsage(commandConfig *CommandConfig) string {
+	return builtinFlagUsage("version", isVersionFlag(commandConfig, "--version"), "V", isVersionFlag(commandConfig, "-V"))
+}
+
+// write the version of the program as a line of text or in JSON format
+func (registry *Registry) writeVersion(w io.Writer, info *VersionInfo, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(info)
+	}
+
+	_, err := fmt.Fprintf(w, "%s %s\n", registry.programName(), info)
+	return err
+}
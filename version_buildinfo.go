@@ -0,0 +1,24 @@
+//go:build go1.18
+// +build go1.18
+
+package clapper
+
+import "runtime/debug"
+
+// read the Go version and the VCS settings of the build information
+func readBuildSettings(build *debug.BuildInfo, info *VersionInfo) {
+	if build.GoVersion != "" {
+		info.GoVersion = build.GoVersion
+	}
+
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.time":
+			info.Time = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+}
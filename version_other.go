@@ -0,0 +1,10 @@
+//go:build !go1.18
+// +build !go1.18
+
+package clapper
+
+import "runtime/debug"
+
+// read the Go version and the VCS settings of the build information (not stamped before Go 1.18)
+func readBuildSettings(build *debug.BuildInfo, info *VersionInfo) {
+}
@@ -0,0 +1,118 @@
+package clapper_test
+
+import (
+	"encoding/json"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/msaf1980/clapper"
+	"github.com/msaf1980/clapper/clappertest"
+)
+
+// test the version flags of the root command
+func TestVersionFlag(t *testing.T) {
+	registry := clapper.NewRegistry()
+	registry.Name = "demo"
+	rootCommand, _ := registry.Register("")
+	rootCommand.AddFlag("verbose", "v", true, "")
+	rootCommand.SetVersion("1.2.3")
+
+	want := "demo 1.2.3 (" + runtime.Version() + ")\n"
+	for _, args := range [][]string{{"--version"}, {"-v", "-V"}} {
+		result := clappertest.Run(t, registry, clappertest.Fixture{Args: args})
+		clappertest.AssertError(t, result.Err, clapper.ErrorVersion{})
+		if result.Stdout != want {
+			t.Errorf("%q: got %q, want %q", args, result.Stdout, want)
+		}
+	}
+	if code := clapper.ExitCode(clapper.ErrorVersion{}); code != clapper.ExitSuccess {
+		t.Errorf("got exit code %d, want %d", code, clapper.ExitSuccess)
+	}
+
+	// JSON format
+	result := clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"--version=json"}})
+	clappertest.AssertError(t, result.Err, clapper.ErrorVersion{})
+	var info clapper.VersionInfo
+	if err := json.Unmarshal([]byte(result.Stdout), &info); err != nil {
+		t.Fatal(err)
+	}
+	if info.Version != "1.2.3" || info.GoVersion != runtime.Version() {
+		t.Errorf("got version %+v", info)
+	}
+
+	result = clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"--version=xml"}})
+	clappertest.AssertError(t, result.Err, clapper.ErrorUnsupportedValue{Name: "version", Value: "xml"})
+
+	if help := clappertest.Help(t, registry, ""); !strings.Contains(help, "  -V, --version   show the version of the program\n") {
+		t.Errorf("got help %q", help)
+	}
+}
+
+// test the version flags with the flags registered by the command
+func TestVersionFlagOverride(t *testing.T) {
+	registry := newDemoRegistry(true)
+	registry.Commands[""].SetVersion("")
+
+	// `-V` is the value flag of the root command
+	command := clappertest.Parse(t, registry, "-V", "2.0.0", "out")
+	clappertest.AssertFlags(t, command, map[string]string{"version": "2.0.0"})
+
+	help := clappertest.Help(t, registry, "")
+	if strings.Contains(help, "show the version of the program") {
+		t.Errorf("got help %q", help)
+	}
+
+	// only `--version` is built in
+	registry.Commands["info"].SetVersion("1.0.0")
+	delete(registry.Commands["info"].Flags, "version")
+	result := clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"info", "--version"}})
+	clappertest.AssertError(t, result.Err, clapper.ErrorVersion{})
+	if help := clappertest.Help(t, registry, "info"); !strings.Contains(help, "      --version ") {
+		t.Errorf("got help %q", help)
+	}
+}
+
+// test the version command
+func TestVersionCommand(t *testing.T) {
+	registry := newDemoRegistry(false)
+	if _, exists := registry.RegisterVersion("v2.0.0"); exists {
+		t.Fatal("version command exists")
+	}
+
+	result := clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"version"}})
+	clappertest.AssertError(t, result.Err, clapper.ErrorVersion{})
+	if want := "demo v2.0.0 (" + runtime.Version() + ")\n"; result.Stdout != want {
+		t.Errorf("got %q, want %q", result.Stdout, want)
+	}
+
+	result = clappertest.Run(t, registry, clappertest.Fixture{Args: []string{"version", "--json"}})
+	clappertest.AssertError(t, result.Err, clapper.ErrorVersion{})
+	if !strings.Contains(result.Stdout, `"version": "v2.0.0"`) {
+		t.Errorf("got %q", result.Stdout)
+	}
+
+	// a registered `version` command is kept
+	registry = newDemoRegistry(false)
+	versionCommand, _ := registry.Register("version")
+	if _, exists := registry.RegisterVersion(""); !exists {
+		t.Fatal("version command replaced")
+	}
+	clappertest.Parse(t, registry, "version")
+	if versionCommand.Version != nil {
+		t.Errorf("got version %v", versionCommand.Version)
+	}
+}
+
+// test the version of the build information
+func TestBuildVersion(t *testing.T) {
+	info := clapper.BuildVersion()
+	if info.Version == "" || info.GoVersion == "" {
+		t.Errorf("got version %+v", info)
+	}
+
+	info = clapper.VersionInfo{Version: "v1.0.0", Revision: "0123456789abcdef", Modified: true, GoVersion: "go1.21.0"}
+	if got, want := info.String(), "v1.0.0 (revision 0123456789ab, modified, go1.21.0)"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}